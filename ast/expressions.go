@@ -198,6 +198,66 @@ func (l *IdentifierList) AddIdentifier(id *Identifier, comma *token.TokenContext
 	l.Identifiers = append(l.Identifiers, item)
 }
 
+// SplitRest splits a spread expression at the end of the list, which is a rest
+// parameter if the list is parameters of a function.
+func (l *ExpressionList) SplitRest() (*ExpressionList, *SpreadExpression) {
+	n := len(l.Expressions)
+	if n == 0 {
+		return l, nil
+	}
+
+	spread, ok := l.Expressions[n-1].Expression.(*SpreadExpression)
+	if !ok {
+		return l, nil
+	}
+
+	list := &ExpressionList{
+		Expressions: l.Expressions[:n-1],
+	}
+
+	return list, spread
+}
+
+func (l *ExpressionList) HasSpread() bool {
+	for _, item := range l.Expressions {
+		if _, ok := item.Expression.(*SpreadExpression); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+type SpreadExpression struct {
+	Ellipsis   *token.TokenContext
+	Expression Expression
+}
+
+func (e *SpreadExpression) expressionNode() {}
+
+func (e *SpreadExpression) CanonicalCode() string {
+	return "..." + e.Expression.CanonicalCode()
+}
+
+func (e *SpreadExpression) GetContext() *token.Context {
+	c := token.JoinContext(
+		e.Ellipsis.ToContext(),
+		e.Expression.GetContext(),
+	)
+
+	return c
+}
+
+func (e *SpreadExpression) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *SpreadExpression:
+		result = e.Expression.EqualTo(n.Expression)
+	}
+
+	return result
+}
+
 type PrefixExpression struct {
 	Prefix  *token.TokenContext
 	Operand Expression
@@ -316,6 +376,424 @@ func (e *IndexExpression) EqualTo(node Node) bool {
 	return result
 }
 
+// SliceExpression
+// base[low:high], both low and high can be omitted.
+type SliceExpression struct {
+	Base     Expression
+	LBracket *token.TokenContext
+	Low      Expression
+	Colon    *token.TokenContext
+	High     Expression
+	RBracket *token.TokenContext
+}
+
+func (e *SliceExpression) expressionNode() {}
+
+func (e *SliceExpression) CanonicalCode() string {
+	low, high := "", ""
+	if e.Low != nil {
+		low = e.Low.CanonicalCode()
+	}
+
+	if e.High != nil {
+		high = e.High.CanonicalCode()
+	}
+
+	s := fmt.Sprintf("(%s[%s:%s])", e.Base.CanonicalCode(), low, high)
+	return s
+}
+
+func (e *SliceExpression) GetContext() *token.Context {
+	c := token.JoinContext(
+		e.Base.GetContext(),
+		e.LBracket.ToContext(),
+		GetContext(e.Low),
+		e.Colon.ToContext(),
+		GetContext(e.High),
+		e.RBracket.ToContext(),
+	)
+
+	return c
+}
+
+func (e *SliceExpression) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *SliceExpression:
+		result = e.Base.EqualTo(n.Base) &&
+			OptionalEqualTo(e.Low, n.Low) &&
+			OptionalEqualTo(e.High, n.High)
+	}
+
+	return result
+}
+
+// AssignExpression
+// variable = value
+// base[index] = value
+// base.key = value
+type AssignExpression struct {
+	Target Expression
+	Assign *token.TokenContext
+	Value  Expression
+}
+
+func (e *AssignExpression) expressionNode() {}
+
+func (e *AssignExpression) CanonicalCode() string {
+	s := fmt.Sprintf("(%s = %s)",
+		e.Target.CanonicalCode(),
+		e.Value.CanonicalCode())
+
+	return s
+}
+
+func (e *AssignExpression) GetContext() *token.Context {
+	c := token.JoinContext(
+		e.Target.GetContext(),
+		e.Assign.ToContext(),
+		e.Value.GetContext(),
+	)
+
+	return c
+}
+
+func (e *AssignExpression) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *AssignExpression:
+		result = e.Target.EqualTo(n.Target) &&
+			e.Value.EqualTo(n.Value)
+	}
+
+	return result
+}
+
+// ArrayPattern destructs an array in let statement.
+// [a, b, ...rest]
+type ArrayPattern struct {
+	LBracket *token.TokenContext
+	Elements *IdentifierList
+	Ellipsis *token.TokenContext
+	Rest     *Identifier
+	RBracket *token.TokenContext
+}
+
+func (p *ArrayPattern) patternNode() {}
+
+func (p *ArrayPattern) CanonicalCode() string {
+	elems := make([]string, 0, p.Elements.Length()+1)
+	for _, item := range p.Elements.Identifiers {
+		elems = append(elems, item.Identifier.CanonicalCode())
+	}
+
+	if p.Rest != nil {
+		elems = append(elems, "..."+p.Rest.CanonicalCode())
+	}
+
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+func (p *ArrayPattern) GetContext() *token.Context {
+	c := token.JoinContext(
+		p.LBracket.ToContext(),
+		p.Elements.GetContext(),
+		p.Ellipsis.ToContext(),
+		GetContext(p.Rest),
+		p.RBracket.ToContext(),
+	)
+
+	return c
+}
+
+func (p *ArrayPattern) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *ArrayPattern:
+		result = p.Elements.EqualTo(n.Elements) &&
+			(p.Rest == nil) == (n.Rest == nil)
+
+		if result && p.Rest != nil {
+			result = p.Rest.EqualTo(n.Rest)
+		}
+	}
+
+	return result
+}
+
+// HashPatternPair binds value of Key to Value, Value is same as Key if it is
+// omitted.
+type HashPatternPair struct {
+	Key   *Identifier
+	Colon *token.TokenContext
+	Value *Identifier
+	Comma *token.TokenContext
+}
+
+func (p *HashPatternPair) CanonicalCode() string {
+	if p.Colon == nil {
+		return p.Key.CanonicalCode()
+	}
+
+	return p.Key.CanonicalCode() + ": " + p.Value.CanonicalCode()
+}
+
+func (p *HashPatternPair) GetContext() *token.Context {
+	var value *token.Context
+	if p.Colon != nil {
+		value = p.Value.GetContext()
+	}
+
+	c := token.JoinContext(
+		p.Key.GetContext(),
+		p.Colon.ToContext(),
+		value,
+		p.Comma.ToContext(),
+	)
+
+	return c
+}
+
+// HashPattern destructs a hash in let statement.
+// {name, age: years}
+type HashPattern struct {
+	LBrace *token.TokenContext
+	Pairs  []*HashPatternPair
+	RBrace *token.TokenContext
+}
+
+func (p *HashPattern) patternNode() {}
+
+func (p *HashPattern) CanonicalCode() string {
+	elems := make([]string, len(p.Pairs))
+	for i, pair := range p.Pairs {
+		elems[i] = pair.CanonicalCode()
+	}
+
+	return "{" + strings.Join(elems, ", ") + "}"
+}
+
+func (p *HashPattern) GetContext() *token.Context {
+	ctxs := []*token.Context{p.LBrace.ToContext()}
+	for _, pair := range p.Pairs {
+		ctxs = append(ctxs, pair.GetContext())
+	}
+	ctxs = append(ctxs, p.RBrace.ToContext())
+
+	return token.JoinContext(ctxs...)
+}
+
+func (p *HashPattern) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *HashPattern:
+		if len(p.Pairs) == len(n.Pairs) {
+			result = true
+			for i, pair := range p.Pairs {
+				if !pair.Key.EqualTo(n.Pairs[i].Key) ||
+					!pair.Value.EqualTo(n.Pairs[i].Value) {
+					result = false
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// LiteralPattern matches a value equal to the literal in match expression.
+// 1, -2.5, "str", true, null
+type LiteralPattern struct {
+	Value Expression
+}
+
+func (p *LiteralPattern) patternNode() {}
+
+func (p *LiteralPattern) CanonicalCode() string {
+	return p.Value.CanonicalCode()
+}
+
+func (p *LiteralPattern) GetContext() *token.Context {
+	return p.Value.GetContext()
+}
+
+func (p *LiteralPattern) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *LiteralPattern:
+		result = p.Value.EqualTo(n.Value)
+	}
+
+	return result
+}
+
+// BindingPattern matches any value in match expression, and binds the value to
+// Name, unless it is the wildcard _.
+type BindingPattern struct {
+	Name *Identifier
+}
+
+func (p *BindingPattern) patternNode() {}
+
+// IsWildcard returns true if the pattern is _, which binds nothing.
+func (p *BindingPattern) IsWildcard() bool {
+	return p.Name.Value == "_"
+}
+
+func (p *BindingPattern) CanonicalCode() string {
+	return p.Name.CanonicalCode()
+}
+
+func (p *BindingPattern) GetContext() *token.Context {
+	return p.Name.GetContext()
+}
+
+func (p *BindingPattern) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *BindingPattern:
+		result = p.Name.EqualTo(n.Name)
+	}
+
+	return result
+}
+
+// ArrayMatchPattern matches an array with the same number of elements in match
+// expression, or at least the number of elements if rest is given.
+// [1, x, ...rest]
+type ArrayMatchPattern struct {
+	LBracket *token.TokenContext
+	Elements []Pattern
+	Ellipsis *token.TokenContext
+	Rest     *Identifier
+	RBracket *token.TokenContext
+}
+
+func (p *ArrayMatchPattern) patternNode() {}
+
+func (p *ArrayMatchPattern) CanonicalCode() string {
+	elems := make([]string, 0, len(p.Elements)+1)
+	for _, elem := range p.Elements {
+		elems = append(elems, elem.CanonicalCode())
+	}
+
+	if p.Rest != nil {
+		elems = append(elems, "..."+p.Rest.CanonicalCode())
+	}
+
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+func (p *ArrayMatchPattern) GetContext() *token.Context {
+	ctxs := []*token.Context{p.LBracket.ToContext()}
+	for _, elem := range p.Elements {
+		ctxs = append(ctxs, elem.GetContext())
+	}
+	ctxs = append(ctxs, p.Ellipsis.ToContext(), p.Rest.GetContext(), p.RBracket.ToContext())
+
+	return token.JoinContext(ctxs...)
+}
+
+func (p *ArrayMatchPattern) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *ArrayMatchPattern:
+		if len(p.Elements) == len(n.Elements) && (p.Rest == nil) == (n.Rest == nil) {
+			result = p.Rest == nil || p.Rest.EqualTo(n.Rest)
+			for i, elem := range p.Elements {
+				if !result {
+					break
+				}
+				result = elem.EqualTo(n.Elements[i])
+			}
+		}
+	}
+
+	return result
+}
+
+// HashMatchPair matches value of Key with Value, Value binds the value to the
+// key name if it is omitted. Key is an identifier or a literal.
+type HashMatchPair struct {
+	Key   Expression
+	Colon *token.TokenContext
+	Value Pattern
+	Comma *token.TokenContext
+}
+
+func (p *HashMatchPair) CanonicalCode() string {
+	if p.Colon == nil {
+		return p.Key.CanonicalCode()
+	}
+
+	return p.Key.CanonicalCode() + ": " + p.Value.CanonicalCode()
+}
+
+func (p *HashMatchPair) GetContext() *token.Context {
+	var value *token.Context
+	if p.Colon != nil {
+		value = p.Value.GetContext()
+	}
+
+	c := token.JoinContext(
+		p.Key.GetContext(),
+		p.Colon.ToContext(),
+		value,
+		p.Comma.ToContext(),
+	)
+
+	return c
+}
+
+// HashMatchPattern matches a hash in match expression, missing keys are null.
+// {kind: "circle", radius}
+type HashMatchPattern struct {
+	LBrace *token.TokenContext
+	Pairs  []*HashMatchPair
+	RBrace *token.TokenContext
+}
+
+func (p *HashMatchPattern) patternNode() {}
+
+func (p *HashMatchPattern) CanonicalCode() string {
+	elems := make([]string, len(p.Pairs))
+	for i, pair := range p.Pairs {
+		elems[i] = pair.CanonicalCode()
+	}
+
+	return "{" + strings.Join(elems, ", ") + "}"
+}
+
+func (p *HashMatchPattern) GetContext() *token.Context {
+	ctxs := []*token.Context{p.LBrace.ToContext()}
+	for _, pair := range p.Pairs {
+		ctxs = append(ctxs, pair.GetContext())
+	}
+	ctxs = append(ctxs, p.RBrace.ToContext())
+
+	return token.JoinContext(ctxs...)
+}
+
+func (p *HashMatchPattern) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *HashMatchPattern:
+		if len(p.Pairs) == len(n.Pairs) {
+			result = true
+			for i, pair := range p.Pairs {
+				if !pair.Key.EqualTo(n.Pairs[i].Key) ||
+					!pair.Value.EqualTo(n.Pairs[i].Value) {
+					result = false
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
+
 // CallExpression
 // Callable()
 // Callable::Member()
@@ -382,7 +860,7 @@ func (e *CallExpression) EqualTo(node Node) bool {
 		}
 
 		result = result && e.Args.EqualTo(n.Args)
-		result = result && e.Token.Token == n.Token.Token && e.Recursion == n.Recursion
+		result = result && e.Token.GetToken() == n.Token.GetToken() && e.Recursion == n.Recursion
 
 		if e.Member != nil {
 			result = result && e.Member.EqualTo(n.Member)
@@ -445,3 +923,103 @@ func (e *IfExpression) EqualTo(node Node) bool {
 
 	return result
 }
+
+// MatchArm is an arm of match expression, Guard is optional.
+// pattern [if guard] => value
+type MatchArm struct {
+	Pattern Pattern
+	If      *token.TokenContext
+	Guard   Expression
+	Arrow   *token.TokenContext
+	Value   Expression
+	Comma   *token.TokenContext
+}
+
+func (a *MatchArm) CanonicalCode() string {
+	s := a.Pattern.CanonicalCode()
+	if a.Guard != nil {
+		s += " if " + a.Guard.CanonicalCode()
+	}
+
+	return s + " => " + a.Value.CanonicalCode()
+}
+
+func (a *MatchArm) GetContext() *token.Context {
+	c := token.JoinContext(
+		a.Pattern.GetContext(),
+		a.If.ToContext(),
+		GetContext(a.Guard),
+		a.Arrow.ToContext(),
+		a.Value.GetContext(),
+		a.Comma.ToContext(),
+	)
+
+	return c
+}
+
+func (a *MatchArm) EqualTo(b *MatchArm) bool {
+	return a.Pattern.EqualTo(b.Pattern) &&
+		OptionalEqualTo(a.Guard, b.Guard) &&
+		a.Value.EqualTo(b.Value)
+}
+
+// MatchExpression
+// match (subject) { pattern [if guard] => value, ... }
+type MatchExpression struct {
+	Match   *token.TokenContext
+	LParen  *token.TokenContext
+	Subject Expression
+	RParen  *token.TokenContext
+	LBrace  *token.TokenContext
+	Arms    []*MatchArm
+	RBrace  *token.TokenContext
+}
+
+func (e *MatchExpression) expressionNode() {}
+
+func (e *MatchExpression) CanonicalCode() string {
+	arms := make([]string, len(e.Arms))
+	for i, arm := range e.Arms {
+		arms[i] = arm.CanonicalCode()
+	}
+
+	return fmt.Sprintf("match ( %s ) { %s }",
+		e.Subject.CanonicalCode(),
+		strings.Join(arms, ", "),
+	)
+}
+
+func (e *MatchExpression) GetContext() *token.Context {
+	ctxs := []*token.Context{
+		e.Match.ToContext(),
+		e.LParen.ToContext(),
+		e.Subject.GetContext(),
+		e.RParen.ToContext(),
+		e.LBrace.ToContext(),
+	}
+
+	for _, arm := range e.Arms {
+		ctxs = append(ctxs, arm.GetContext())
+	}
+	ctxs = append(ctxs, e.RBrace.ToContext())
+
+	return token.JoinContext(ctxs...)
+}
+
+func (e *MatchExpression) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *MatchExpression:
+		if e.Subject.EqualTo(n.Subject) && len(e.Arms) == len(n.Arms) {
+			result = true
+			for i, arm := range e.Arms {
+				if !arm.EqualTo(n.Arms[i]) {
+					result = false
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -127,6 +127,56 @@ func (s *StringLiteral) EqualTo(node Node) bool {
 	return result
 }
 
+// InterpolatedString is a string literal with embedded expressions, the value
+// is concatenation of all parts. Parts are pieces of characters as string
+// literals without quotes, and embedded expressions.
+// "hello ${name}"
+type InterpolatedString struct {
+	Parts   []Expression
+	Content string
+	Context *token.Context
+}
+
+func (s *InterpolatedString) expressionNode() {}
+
+func (s *InterpolatedString) CanonicalCode() string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, part := range s.Parts {
+		if literal, ok := part.(*StringLiteral); ok {
+			b.WriteString(literal.Content)
+
+		} else {
+			b.WriteString("${" + part.CanonicalCode() + "}")
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func (s *InterpolatedString) GetContext() *token.Context {
+	return s.Context
+}
+
+func (s *InterpolatedString) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *InterpolatedString:
+		if len(s.Parts) == len(n.Parts) {
+			result = true
+			for i, part := range s.Parts {
+				if !part.EqualTo(n.Parts[i]) {
+					result = false
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
+
 type BooleanLiteral struct {
 	Value   bool
 	Context *token.Context
@@ -315,6 +365,8 @@ type FunctionLiteral struct {
 	Function     *token.TokenContext
 	LParen       *token.TokenContext
 	Arguments    *IdentifierList
+	Ellipsis     *token.TokenContext
+	Rest         *Identifier // rest parameter collects extra arguments
 	RParen       *token.TokenContext
 	Body         *BlockStatement
 	ReturnValues int
@@ -323,19 +375,41 @@ type FunctionLiteral struct {
 func (f *FunctionLiteral) expressionNode() {}
 
 func (f *FunctionLiteral) CanonicalCode() string {
+	args := f.Arguments.CanonicalCode()
+	if f.Rest != nil {
+		rest := "..." + f.Rest.CanonicalCode()
+		if len(args) > 0 {
+			args += ", " + rest
+		} else {
+			args = rest
+		}
+	}
+
 	result := fmt.Sprintf("fn(%s) %s",
-		f.Arguments.CanonicalCode(),
+		args,
 		f.Body.CanonicalCode(),
 	)
 
 	return result
 }
 
+// IsVariadic checks whether the function has a rest parameter.
+func (f *FunctionLiteral) IsVariadic() bool {
+	return f.Rest != nil
+}
+
 func (f *FunctionLiteral) GetContext() *token.Context {
+	var rest *token.Context
+	if f.Rest != nil {
+		rest = f.Rest.GetContext()
+	}
+
 	c := token.JoinContext(
 		f.Function.ToContext(),
 		f.LParen.ToContext(),
 		f.Arguments.GetContext(),
+		f.Ellipsis.ToContext(),
+		rest,
 		f.RParen.ToContext(),
 		f.Body.GetContext(),
 	)
@@ -351,6 +425,11 @@ func (f *FunctionLiteral) EqualTo(node Node) bool {
 			break
 		}
 
+		if (f.Rest == nil) != (n.Rest == nil) ||
+			(f.Rest != nil && !f.Rest.EqualTo(n.Rest)) {
+			break
+		}
+
 		if ok := f.Body.EqualTo(n.Body); !ok {
 			break
 		}
@@ -43,6 +43,13 @@ type BlockStatementNode interface {
 	blockStatementNode()
 }
 
+// Pattern is the left side of a destructuring let statement, or the pattern
+// of an arm in match expression.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
 type Program struct {
 	Statements []Statement
 }
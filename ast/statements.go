@@ -24,6 +24,7 @@ type LetStatement struct {
 
 	Let         *token.TokenContext
 	Identifiers *IdentifierList
+	Pattern     Pattern // destructuring pattern, instead of identifiers
 	Assign      *token.TokenContext
 	Expressions *ExpressionList
 	Semicolon   *token.TokenContext
@@ -32,9 +33,29 @@ type LetStatement struct {
 func (s *LetStatement) statementNode()     {}
 func (s *LetStatement) lineStatementNode() {}
 
+// IsVar returns true if the statement declares mutable variables with var.
+func (s *LetStatement) IsVar() bool {
+	return s.Let.GetToken() == token.Var
+}
+
+// Target returns the identifier list or the pattern being declared.
+func (s *LetStatement) Target() Node {
+	if s.Pattern != nil {
+		return s.Pattern
+	}
+
+	return s.Identifiers
+}
+
 func (s *LetStatement) CanonicalCode() string {
-	result := fmt.Sprintf("let %s = %s;",
-		s.Identifiers.CanonicalCode(),
+	keyword := token.SLet
+	if s.IsVar() {
+		keyword = token.SVar
+	}
+
+	result := fmt.Sprintf("%s %s = %s;",
+		keyword,
+		s.Target().CanonicalCode(),
 		s.Expressions.CanonicalCode(),
 	)
 
@@ -44,7 +65,7 @@ func (s *LetStatement) CanonicalCode() string {
 func (s *LetStatement) GetContext() *token.Context {
 	c := token.JoinContext(
 		s.Let.ToContext(),
-		s.Identifiers.GetContext(),
+		s.Target().GetContext(),
 		s.Assign.ToContext(),
 		s.Expressions.GetContext(),
 		s.Semicolon.ToContext(),
@@ -57,7 +78,8 @@ func (s *LetStatement) EqualTo(node Node) bool {
 	result := false
 	switch n := node.(type) {
 	case *LetStatement:
-		result = s.Identifiers.EqualTo(n.Identifiers) &&
+		result = s.IsVar() == n.IsVar() &&
+			s.Target().EqualTo(n.Target()) &&
 			s.Expressions.EqualTo(n.Expressions)
 	}
 
@@ -212,23 +234,38 @@ func (s *ExpressionStatement) EqualTo(node Node) bool {
 	return result
 }
 
-// ImportStatement is not determined yet.
+// ImportStatement imports a module from a file, and binds the value returned
+// by the module to a variable.
+// - import "path/to/module";
+// - import name "path/to/module";
 type ImportStatement struct {
 	StatementBase
 
-	Import *token.TokenContext
-	Target *token.TokenContext
+	Import    *token.TokenContext
+	Name      *Identifier
+	Target    *token.TokenContext
+	Path      string
+	Semicolon *token.TokenContext
 }
 
 func (s *ImportStatement) statementNode()     {}
 func (s *ImportStatement) lineStatementNode() {}
 
 func (s *ImportStatement) CanonicalCode() string {
-	return "import;"
+	if s.Name != nil {
+		return fmt.Sprintf("import %s %s;", s.Name.CanonicalCode(), s.Target.Content)
+	}
+
+	return fmt.Sprintf("import %s;", s.Target.Content)
 }
 
 func (s *ImportStatement) GetContext() *token.Context {
-	c := token.NewContext(s.Import, s.Target)
+	c := token.JoinContext(
+		s.Import.ToContext(),
+		s.Name.GetContext(),
+		s.Target.ToContext(),
+		s.Semicolon.ToContext(),
+	)
 
 	return c
 }
@@ -240,7 +277,288 @@ func (s *ImportStatement) EqualTo(node Node) bool {
 		if s.Target != nil && n.Target != nil {
 			result = s.Target.Content == n.Target.Content
 		}
+
+		if s.Name != nil || n.Name != nil {
+			result = result && s.Name != nil && s.Name.EqualTo(n.Name)
+		}
+	}
+
+	return result
+}
+
+// ThrowStatement throws a value as an exception.
+// - throw expression;
+type ThrowStatement struct {
+	StatementBase
+
+	Throw      *token.TokenContext
+	Expression Expression
+	Semicolon  *token.TokenContext
+}
+
+func (s *ThrowStatement) statementNode()     {}
+func (s *ThrowStatement) lineStatementNode() {}
+
+func (s *ThrowStatement) CanonicalCode() string {
+	return fmt.Sprintf("throw %s;", s.Expression.CanonicalCode())
+}
+
+func (s *ThrowStatement) GetContext() *token.Context {
+	c := token.JoinContext(
+		s.Throw.ToContext(),
+		s.Expression.GetContext(),
+		s.Semicolon.ToContext(),
+	)
+
+	return c
+}
+
+func (s *ThrowStatement) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *ThrowStatement:
+		result = s.Expression.EqualTo(n.Expression)
+	}
+
+	return result
+}
+
+// TryStatement runs a block, and catches exceptions thrown in it. Finally block
+// is always run when leaving the statement. At least one of catch block and
+// finally block is required.
+// - try { ... } catch (e) { ... }
+// - try { ... } finally { ... }
+// - try { ... } catch (e) { ... } finally { ... }
+type TryStatement struct {
+	StatementBase
+
+	Try         *token.TokenContext
+	Body        *BlockStatement
+	Catch       *token.TokenContext
+	LParen      *token.TokenContext
+	Variable    *Identifier
+	RParen      *token.TokenContext
+	CatchBody   *BlockStatement
+	Finally     *token.TokenContext
+	FinallyBody *BlockStatement
+}
+
+func (s *TryStatement) statementNode()      {}
+func (s *TryStatement) blockStatementNode() {}
+
+func (s *TryStatement) CanonicalCode() string {
+	result := fmt.Sprintf("try %s", s.Body.CanonicalCode())
+	if s.CatchBody != nil {
+		result += fmt.Sprintf(" catch ( %s ) %s",
+			s.Variable.CanonicalCode(),
+			s.CatchBody.CanonicalCode(),
+		)
+	}
+
+	if s.FinallyBody != nil {
+		result += fmt.Sprintf(" finally %s", s.FinallyBody.CanonicalCode())
+	}
+
+	return result
+}
+
+func (s *TryStatement) GetContext() *token.Context {
+	ctxList := []*token.Context{
+		s.Try.ToContext(),
+		s.Body.GetContext(),
+	}
+
+	if s.CatchBody != nil {
+		ctxList = append(ctxList,
+			s.Catch.ToContext(),
+			s.LParen.ToContext(),
+			s.Variable.GetContext(),
+			s.RParen.ToContext(),
+			s.CatchBody.GetContext(),
+		)
+	}
+
+	if s.FinallyBody != nil {
+		ctxList = append(ctxList,
+			s.Finally.ToContext(),
+			s.FinallyBody.GetContext(),
+		)
+	}
+
+	return token.JoinContext(ctxList...)
+}
+
+func (s *TryStatement) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *TryStatement:
+		result = s.Body.EqualTo(n.Body) &&
+			(s.CatchBody == nil) == (n.CatchBody == nil) &&
+			(s.FinallyBody == nil) == (n.FinallyBody == nil)
+
+		if result && s.CatchBody != nil {
+			result = s.Variable.EqualTo(n.Variable) &&
+				s.CatchBody.EqualTo(n.CatchBody)
+		}
+
+		if result && s.FinallyBody != nil {
+			result = s.FinallyBody.EqualTo(n.FinallyBody)
+		}
+	}
+
+	return result
+}
+
+// WhileStatement runs body while condition is true.
+// - while (condition) { ... }
+type WhileStatement struct {
+	StatementBase
+
+	While     *token.TokenContext
+	LParen    *token.TokenContext
+	Condition Expression
+	RParen    *token.TokenContext
+	Body      *BlockStatement
+}
+
+func (s *WhileStatement) statementNode()      {}
+func (s *WhileStatement) blockStatementNode() {}
+
+func (s *WhileStatement) CanonicalCode() string {
+	return fmt.Sprintf("while ( %s ) %s",
+		s.Condition.CanonicalCode(),
+		s.Body.CanonicalCode(),
+	)
+}
+
+func (s *WhileStatement) GetContext() *token.Context {
+	c := token.JoinContext(
+		s.While.ToContext(),
+		s.LParen.ToContext(),
+		s.Condition.GetContext(),
+		s.RParen.ToContext(),
+		s.Body.GetContext(),
+	)
+
+	return c
+}
+
+func (s *WhileStatement) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *WhileStatement:
+		result = s.Condition.EqualTo(n.Condition) &&
+			s.Body.EqualTo(n.Body)
 	}
 
 	return result
 }
+
+// ForStatement runs body with each element of iterable.
+// - for (variable in iterable) { ... }
+type ForStatement struct {
+	StatementBase
+
+	For      *token.TokenContext
+	LParen   *token.TokenContext
+	Variable *Identifier
+	In       *token.TokenContext
+	Iterable Expression
+	RParen   *token.TokenContext
+	Body     *BlockStatement
+}
+
+func (s *ForStatement) statementNode()      {}
+func (s *ForStatement) blockStatementNode() {}
+
+func (s *ForStatement) CanonicalCode() string {
+	return fmt.Sprintf("for ( %s in %s ) %s",
+		s.Variable.CanonicalCode(),
+		s.Iterable.CanonicalCode(),
+		s.Body.CanonicalCode(),
+	)
+}
+
+func (s *ForStatement) GetContext() *token.Context {
+	c := token.JoinContext(
+		s.For.ToContext(),
+		s.LParen.ToContext(),
+		s.Variable.GetContext(),
+		s.In.ToContext(),
+		s.Iterable.GetContext(),
+		s.RParen.ToContext(),
+		s.Body.GetContext(),
+	)
+
+	return c
+}
+
+func (s *ForStatement) EqualTo(node Node) bool {
+	result := false
+	switch n := node.(type) {
+	case *ForStatement:
+		result = s.Variable.EqualTo(n.Variable) &&
+			s.Iterable.EqualTo(n.Iterable) &&
+			s.Body.EqualTo(n.Body)
+	}
+
+	return result
+}
+
+// BreakStatement leaves the innermost loop.
+type BreakStatement struct {
+	StatementBase
+
+	Break     *token.TokenContext
+	Semicolon *token.TokenContext
+}
+
+func (s *BreakStatement) statementNode()     {}
+func (s *BreakStatement) lineStatementNode() {}
+
+func (s *BreakStatement) CanonicalCode() string {
+	return "break;"
+}
+
+func (s *BreakStatement) GetContext() *token.Context {
+	c := token.JoinContext(
+		s.Break.ToContext(),
+		s.Semicolon.ToContext(),
+	)
+
+	return c
+}
+
+func (s *BreakStatement) EqualTo(node Node) bool {
+	_, ok := node.(*BreakStatement)
+	return ok
+}
+
+// ContinueStatement starts the next iteration of the innermost loop.
+type ContinueStatement struct {
+	StatementBase
+
+	Continue  *token.TokenContext
+	Semicolon *token.TokenContext
+}
+
+func (s *ContinueStatement) statementNode()     {}
+func (s *ContinueStatement) lineStatementNode() {}
+
+func (s *ContinueStatement) CanonicalCode() string {
+	return "continue;"
+}
+
+func (s *ContinueStatement) GetContext() *token.Context {
+	c := token.JoinContext(
+		s.Continue.ToContext(),
+		s.Semicolon.ToContext(),
+	)
+
+	return c
+}
+
+func (s *ContinueStatement) EqualTo(node Node) bool {
+	_, ok := node.(*ContinueStatement)
+	return ok
+}
@@ -21,3 +21,12 @@ func ContextGroup(nodes ...Node) *token.Context {
 
 	return token.JoinContext(ctxList...)
 }
+
+// OptionalEqualTo compares two optional nodes, which are equal if both are nil.
+func OptionalEqualTo(a Node, b Node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.EqualTo(b)
+}
@@ -52,6 +52,11 @@ func doWalk(node Node, v Visitor) {
 	case *Identifier, *IntegerLiteral, *FloatLiteral, *StringLiteral, *BooleanLiteral, *NullLiteral:
 		// do nothing, node has already sent to visitor
 
+	case *InterpolatedString:
+		for _, part := range n.Parts {
+			doWalk(part, v)
+		}
+
 	case *ArrayLiteral:
 		walkOnExpressions(n.Expressions, v)
 
@@ -71,6 +76,43 @@ func doWalk(node Node, v Visitor) {
 			doWalk(id.Identifier, v)
 		}
 
+	case *ArrayPattern:
+		doWalk(n.Elements, v)
+		if n.Rest != nil {
+			doWalk(n.Rest, v)
+		}
+
+	case *HashPattern:
+		for _, pair := range n.Pairs {
+			doWalk(pair.Key, v)
+			if pair.Value != pair.Key {
+				doWalk(pair.Value, v)
+			}
+		}
+
+	case *LiteralPattern:
+		doWalk(n.Value, v)
+
+	case *BindingPattern:
+		doWalk(n.Name, v)
+
+	case *ArrayMatchPattern:
+		for _, elem := range n.Elements {
+			doWalk(elem, v)
+		}
+
+		if n.Rest != nil {
+			doWalk(n.Rest, v)
+		}
+
+	case *HashMatchPattern:
+		for _, pair := range n.Pairs {
+			if pair.Colon != nil {
+				doWalk(pair.Key, v)
+			}
+			doWalk(pair.Value, v)
+		}
+
 	case *PrefixExpression:
 		doWalk(n.Operand, v)
 
@@ -82,6 +124,18 @@ func doWalk(node Node, v Visitor) {
 		doWalk(n.Base, v)
 		doWalk(n.Index, v)
 
+	case *SliceExpression:
+		doWalk(n.Base, v)
+		doWalk(n.Low, v)
+		doWalk(n.High, v)
+
+	case *SpreadExpression:
+		doWalk(n.Expression, v)
+
+	case *AssignExpression:
+		doWalk(n.Target, v)
+		doWalk(n.Value, v)
+
 	case *CallExpression:
 		doWalk(n.Base, v)
 		doWalk(n.Member, v) // it is safe to call doWalk with nil
@@ -92,8 +146,18 @@ func doWalk(node Node, v Visitor) {
 		doWalk(n.Consequence, v)
 		doWalk(n.Alternative, v)
 
+	case *MatchExpression:
+		doWalk(n.Subject, v)
+		for _, arm := range n.Arms {
+			doWalk(arm.Pattern, v)
+			if arm.Guard != nil {
+				doWalk(arm.Guard, v)
+			}
+			doWalk(arm.Value, v)
+		}
+
 	case *LetStatement:
-		doWalk(n.Identifiers, v)
+		doWalk(n.Target(), v)
 		doWalk(n.Expressions, v)
 
 	case *ReturnStatement:
@@ -109,7 +173,32 @@ func doWalk(node Node, v Visitor) {
 		doWalk(n.Expressions, v)
 
 	case *ImportStatement:
+		if n.Name != nil {
+			doWalk(n.Name, v)
+		}
+
+	case *ThrowStatement:
+		doWalk(n.Expression, v)
+
+	case *TryStatement:
+		doWalk(n.Body, v)
+		if n.CatchBody != nil {
+			doWalk(n.Variable, v)
+			doWalk(n.CatchBody, v)
+		}
+
+		if n.FinallyBody != nil {
+			doWalk(n.FinallyBody, v)
+		}
+
+	case *WhileStatement:
+		doWalk(n.Condition, v)
+		doWalk(n.Body, v)
 
+	case *ForStatement:
+		doWalk(n.Variable, v)
+		doWalk(n.Iterable, v)
+		doWalk(n.Body, v)
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/flily/macaque-lang/compiler"
 	"github.com/flily/macaque-lang/vm"
@@ -13,6 +14,7 @@ import (
 type Arguments struct {
 	CompileMode     bool
 	InteractiveMode bool
+	SearchPath      string
 	Files           []string
 }
 
@@ -30,13 +32,28 @@ func readFile(filename string) []byte {
 	return content
 }
 
-func execFile(filename string) {
-	_, page, err := compiler.CompileFile(filename)
+// printWarnings prints warnings of compiler, and clears them.
+func printWarnings(c *compiler.Compiler) {
+	for _, w := range c.Warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+
+	c.Warnings = nil
+}
+
+func execFile(args *Arguments, filename string) {
+	c := compiler.NewCompiler()
+	c.AddSearchPath(filepath.SplitList(args.SearchPath)...)
+	block, err := c.CompileFile(filename)
 	if err != nil {
 		fmt.Printf("compile file %s error.\n%s\n", filename, err)
 		return
 	}
 
+	printWarnings(c)
+
+	page := c.Link(block)
+
 	machine := vm.NewNaiveVM()
 	machine.LoadCodePage(page)
 	main := page.Main().Func(nil)
@@ -67,10 +84,11 @@ func main() {
 
 	flag.BoolVar(&args.CompileMode, "c", false, "Compile mode")
 	flag.BoolVar(&args.InteractiveMode, "i", false, "Interactive mode")
+	flag.StringVar(&args.SearchPath, "I", "", "Module search path, separated by "+string(filepath.ListSeparator))
 	flag.Parse()
 
 	if flag.NArg() < 0 {
-		fmt.Println("Usage: macaque [-c] [-i] <file>")
+		fmt.Println("Usage: macaque [-c] [-i] [-I path] <file>")
 		return
 	}
 
@@ -82,6 +100,6 @@ func main() {
 	if args.InteractiveMode {
 		Repl(args)
 	} else {
-		execFile(args.Files[0])
+		execFile(args, args.Files[0])
 	}
 }
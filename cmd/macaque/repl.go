@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/flily/macaque-lang/compiler"
 	"github.com/flily/macaque-lang/vm"
@@ -11,21 +12,24 @@ import (
 
 func Repl(args *Arguments) {
 	m := vm.NewNaiveVMInterpreter()
-	var cc *compiler.Compiler
+	cc := compiler.NewCompiler()
+	cc.AddSearchPath(filepath.SplitList(args.SearchPath)...)
 
 	if len(args.Files) > 0 {
 		filename := args.Files[0]
-		c, page, err := compiler.CompileFile(filename)
+		block, err := cc.CompileFile(filename)
 		if err != nil {
 			fmt.Printf("compile file %s error.\n%s\n", filename, err)
 			return
 		}
 
-		cc = c
-		m.LoadCodePage(page)
+		printWarnings(cc)
+		m.LoadCodePage(cc.Link(block))
 	}
 
+	// Scripts read lines by readline from the same buffered input.
 	reader := bufio.NewReader(os.Stdin)
+	m.SetInput(reader)
 	for {
 		fmt.Print(">>> ")
 		input, err := reader.ReadSlice('\n')
@@ -40,6 +44,8 @@ func Repl(args *Arguments) {
 			return
 		}
 
+		printWarnings(cc)
+
 		if m.CodePage != nil {
 			m.MergeCodeBlock(code, cc.Context)
 
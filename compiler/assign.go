@@ -0,0 +1,96 @@
+package compiler
+
+import (
+	"github.com/flily/macaque-lang/ast"
+	"github.com/flily/macaque-lang/opcode"
+)
+
+// compileVariableStore stores TOS to variable name declared by statement n.
+// Value of mutable local variable is wrapped in a cell, so closures capturing
+// it share the same value. Module and global variables are stored to data
+// areas of VM directly.
+func (c *Compiler) compileVariableStore(n *ast.LetStatement, name string, offset int, r *opcode.CodeBlock) {
+	ctx := n.GetContext()
+	switch NameScope(name) {
+	case FrameScopeModule:
+		r.IL(ctx, opcode.IMStore, offset)
+
+	case FrameScopeGlobal:
+		r.IL(ctx, opcode.IGStore, offset)
+
+	default:
+		if n.IsVar() {
+			r.IL(ctx, opcode.IMakeCell)
+		}
+
+		r.IL(ctx, opcode.ISStore, offset)
+	}
+}
+
+// compileAssignExpression compiles assignment, the value of expression is the
+// assigned value.
+//
+//	a = v               @a = v              a[i] = v
+//	    <v>                 <v>                 <a>
+//	    SDUP                SDUP                <i>
+//	    SLOAD    a          MSTORE   @a         <v>
+//	    SETREF                                  SETINDEX
+func (c *Compiler) compileAssignExpression(n *ast.AssignExpression) (*opcode.CodeBlock, error) {
+	switch target := n.Target.(type) {
+	case *ast.Identifier:
+		return c.compileAssignVariable(n, target)
+
+	case *ast.IndexExpression:
+		r, err := c.compileIndexOperands(target)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.Append(c.compileExpression(n.Value, NewFlag(FlagPackValue))); err != nil {
+			return nil, err
+		}
+
+		r.IL(n.GetContext(), opcode.ISetIndex)
+		r.SetValues(1)
+		return r, nil
+	}
+
+	return nil, NewSemanticError(n.Target.GetContext(),
+		"%s can not be assigned", n.Target.CanonicalCode())
+}
+
+func (c *Compiler) compileAssignVariable(n *ast.AssignExpression, target *ast.Identifier) (*opcode.CodeBlock, error) {
+	name := target.Value
+	ctx := target.GetContext()
+	info, kind := c.Context.Variable.Reference(name)
+	if kind == VariableKindMiss {
+		return nil, NewSemanticError(ctx, "variable %s undefined", name)
+	}
+
+	if !info.Mutable {
+		e := NewSemanticError(ctx, "can not assign to immutable variable %s", name).
+			WithInfo(info.Context, "variable %s is declared here", name)
+		return nil, e
+	}
+
+	r, err := c.compileExpression(n.Value, NewFlag(FlagPackValue))
+	if err != nil {
+		return nil, err
+	}
+
+	r.IL(n.GetContext(), opcode.ISDUP)
+	switch kind {
+	case VariableKindModule:
+		r.IL(n.GetContext(), opcode.IMStore, info.Offset)
+
+	case VariableKindGlobal:
+		r.IL(n.GetContext(), opcode.IGStore, info.Offset)
+
+	default:
+		c.compileIdentifierReference(name, ctx, r)
+		r.IL(n.GetContext(), opcode.ISetRef)
+	}
+
+	r.SetValues(1)
+	return r, nil
+}
@@ -0,0 +1,166 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/opcode"
+	"github.com/flily/macaque-lang/token"
+)
+
+func TestCompileAssignExpression(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			`var a, b = 1; a = a + 1;`,
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadNull),
+				inst(opcode.IMakeCell),
+				inst(opcode.ISStore, 2),
+				inst(opcode.IMakeCell),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.IDeref),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IBinOp, int(token.Plus)),
+				inst(opcode.ISDUP),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ISetRef),
+			),
+			data(),
+		},
+		{
+			text(
+				"var n = 0;",
+				"let f = fn() { n = 1 };",
+			),
+			code(
+				inst(opcode.ILoadInt, 0),
+				inst(opcode.IMakeCell),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.IMakeFunc, 1, 1),
+				inst(opcode.ISStore, 2),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISDUP),
+				inst(opcode.ILoadBind, 0),
+				inst(opcode.ISetRef),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+		{
+			`let h = {}; h.key = 1`,
+			code(
+				inst(opcode.IMakeHash, 0),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ILoad, 0),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISetIndex),
+			),
+			data(object.NewString("key")),
+		},
+		{
+			text(
+				"var @n, $m = 0;",
+				"let f = fn() { @n = $m = 1 };",
+			),
+			code(
+				inst(opcode.ILoadInt, 0),
+				inst(opcode.ILoadNull),
+				inst(opcode.IGStore, 0),
+				inst(opcode.IMStore, 0),
+				inst(opcode.IClean),
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISDUP),
+				inst(opcode.IGStore, 0),
+				inst(opcode.ISDUP),
+				inst(opcode.IMStore, 0),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
+func TestCompileAssignExpressionError(t *testing.T) {
+	tests := []testCompilerErrorCase{
+		{
+			`let a = 1; a = 2;`,
+			text(
+				"let a = 1; a = 2;",
+				"           ^",
+				"           can not assign to immutable variable a",
+				"  at testcase:1:12",
+				"let a = 1; a = 2;",
+				"    ^",
+				"    variable a is declared here",
+				"  at testcase:1:5",
+			),
+		},
+		{
+			`let f = fn(x) { x = 1 };`,
+			text(
+				"let f = fn(x) { x = 1 };",
+				"                ^",
+				"                can not assign to immutable variable x",
+				"  at testcase:1:17",
+				"let f = fn(x) { x = 1 };",
+				"           ^",
+				"           variable x is declared here",
+				"  at testcase:1:12",
+			),
+		},
+		{
+			`let @a = 1; @a = 2;`,
+			text(
+				"let @a = 1; @a = 2;",
+				"            ^^",
+				"            can not assign to immutable variable @a",
+				"  at testcase:1:13",
+				"let @a = 1; @a = 2;",
+				"    ^^",
+				"    variable @a is declared here",
+				"  at testcase:1:5",
+			),
+		},
+		{
+			`b = 1;`,
+			text(
+				"b = 1;",
+				"^",
+				"variable b undefined",
+				"  at testcase:1:1",
+			),
+		},
+		{
+			`var a = 1; var a = 2;`,
+			text(
+				"var a = 1; var a = 2;",
+				"               ^",
+				"               variable a redeclared",
+				"  at testcase:1:16",
+				"var a = 1; var a = 2;",
+				"    ^",
+				"    variable a is already declared here",
+				"  at testcase:1:5",
+			),
+		},
+	}
+
+	runCompilerErrorTestCases(t, tests)
+}
@@ -0,0 +1,88 @@
+package compiler
+
+import (
+	"github.com/flily/macaque-lang/ast"
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/opcode"
+)
+
+// operandArgs makes number of arguments the operand of builtin instruction.
+const operandArgs = -1
+
+// builtinFunction is a function provided by VM, compiled to an instruction with
+// number of arguments, or a fixed value, as operand.
+type builtinFunction struct {
+	code    int
+	minArgs int
+	maxArgs int // -1 for any number of arguments
+	operand int
+}
+
+// Builtin functions can only be called directly, and are shadowed by variables
+// and native functions with the same name.
+var builtinFunctions = map[string]builtinFunction{
+	"pcall":       {opcode.IPCall, 1, -1, operandArgs},
+	"xpcall":      {opcode.IXPCall, 2, -1, operandArgs},
+	"traceback":   {opcode.ITraceback, 0, 0, operandArgs},
+	"typeof":      {opcode.ITypeOf, 1, 1, operandArgs},
+	"funcinfo":    {opcode.IFuncInfo, 1, 1, operandArgs},
+	"print":       {opcode.IPrint, 0, -1, operandArgs},
+	"println":     {opcode.IPrintLn, 0, -1, operandArgs},
+	"printf":      {opcode.IPrintf, 1, -1, operandArgs},
+	"readline":    {opcode.IReadLine, 0, 0, operandArgs},
+	"is_null":     {opcode.IIsType, 1, 1, int(object.ObjectTypeNull)},
+	"is_bool":     {opcode.IIsType, 1, 1, int(object.ObjectTypeBoolean)},
+	"is_int":      {opcode.IIsType, 1, 1, int(object.ObjectTypeInteger)},
+	"is_float":    {opcode.IIsType, 1, 1, int(object.ObjectTypeFloat)},
+	"is_string":   {opcode.IIsType, 1, 1, int(object.ObjectTypeString)},
+	"is_array":    {opcode.IIsType, 1, 1, int(object.ObjectTypeArray)},
+	"is_hash":     {opcode.IIsType, 1, 1, int(object.ObjectTypeHash)},
+	"is_callable": {opcode.IIsType, 1, 1, int(object.ObjectTypeFunction)},
+}
+
+func (c *Compiler) getBuiltinFunction(callable ast.Expression) (builtinFunction, bool) {
+	ident, ok := callable.(*ast.Identifier)
+	if !ok {
+		return builtinFunction{}, false
+	}
+
+	f, ok := builtinFunctions[ident.Value]
+	if !ok {
+		return builtinFunction{}, false
+	}
+
+	if _, kind := c.Context.Variable.Reference(ident.Value); kind != VariableKindMiss {
+		return builtinFunction{}, false
+	}
+
+	if _, ok := c.Context.Natives.Lookup(ident.Value); ok {
+		return builtinFunction{}, false
+	}
+
+	return f, true
+}
+
+// compileBuiltinCall checks number of argument expressions of builtin call, and
+// makes its instruction. Each argument expression is pushed as a single value.
+func (c *Compiler) compileBuiltinCall(expr *ast.CallExpression, f builtinFunction) (*opcode.CodeBlock, error) {
+	name := expr.Base.(*ast.Identifier).Value
+	args := expr.Args.Length()
+	if args < f.minArgs {
+		return nil, NewSemanticError(expr.GetContext(),
+			"%s requires at least %d arguments, but got %d", name, f.minArgs, args)
+	}
+
+	if f.maxArgs >= 0 && args > f.maxArgs {
+		return nil, NewSemanticError(expr.GetContext(),
+			"%s accepts at most %d arguments, but got %d", name, f.maxArgs, args)
+	}
+
+	operand := f.operand
+	if operand == operandArgs {
+		operand = args
+	}
+
+	r := opcode.NewCodeBlock()
+	r.IL(expr.GetContext(), f.code, operand)
+	return r, nil
+}
@@ -0,0 +1,182 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/opcode"
+)
+
+func TestCompileBuiltinFunctions(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			`pcall(1, 2)`,
+			code(
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IPCall, 2),
+			),
+			data(),
+		},
+		{
+			`xpcall(1, 2, 3)`,
+			code(
+				inst(opcode.ILoadInt, 3),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IXPCall, 3),
+			),
+			data(),
+		},
+		{
+			`pcall([1][0], 2)`,
+			code(
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IMakeList, 1),
+				inst(opcode.ILoadInt, 0),
+				inst(opcode.IIndex),
+				inst(opcode.IPCall, 2),
+			),
+			data(),
+		},
+		{
+			`traceback()`,
+			code(
+				inst(opcode.ITraceback, 0),
+			),
+			data(),
+		},
+		{
+			`typeof(1)`,
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ITypeOf, 1),
+			),
+			data(),
+		},
+		{
+			`is_int(1)`,
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IIsType, int(object.ObjectTypeInteger)),
+			),
+			data(),
+		},
+		{
+			`funcinfo(fn() { 1 })`,
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.IFuncInfo, 1),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+		{
+			`println(1, 2)`,
+			code(
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IPrintLn, 2),
+			),
+			data(),
+		},
+		{
+			`printf("%d", 1)`,
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoad, 0),
+				inst(opcode.IPrintf, 2),
+			),
+			data(object.NewString("%d")),
+		},
+		{
+			`print(readline())`,
+			code(
+				inst(opcode.IReadLine, 0),
+				inst(opcode.IPrint, 1),
+			),
+			data(),
+		},
+		{
+			// Builtin functions are shadowed by variables.
+			`let pcall = 1; pcall(2)`,
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ICall, 1),
+			),
+			data(),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
+func TestCompileBuiltinFunctionsError(t *testing.T) {
+	tests := []testCompilerErrorCase{
+		{
+			`pcall()`,
+			text(
+				`pcall()`,
+				`^^^^^^^`,
+				`pcall requires at least 1 arguments, but got 0`,
+				`  at testcase:1:1`,
+			),
+		},
+		{
+			`traceback(1)`,
+			text(
+				`traceback(1)`,
+				`^^^^^^^^^^^^`,
+				`traceback accepts at most 0 arguments, but got 1`,
+				`  at testcase:1:1`,
+			),
+		},
+		{
+			`is_callable(1, 2)`,
+			text(
+				`is_callable(1, 2)`,
+				`^^^^^^^^^^^^^^ ^^`,
+				`is_callable accepts at most 1 arguments, but got 2`,
+				`  at testcase:1:1`,
+			),
+		},
+		{
+			`printf()`,
+			text(
+				`printf()`,
+				`^^^^^^^^`,
+				`printf requires at least 1 arguments, but got 0`,
+				`  at testcase:1:1`,
+			),
+		},
+		{
+			`pcall(...[1])`,
+			text(
+				`pcall(...[1])`,
+				`^^^^^^^^^^^^^`,
+				`spread arguments are not accepted by pcall`,
+				`  at testcase:1:1`,
+			),
+		},
+		{
+			`pcall`,
+			text(
+				`pcall`,
+				`^^^^^`,
+				`variable pcall undefined`,
+				`  at testcase:1:1`,
+			),
+		},
+	}
+
+	runCompilerErrorTestCases(t, tests)
+}
@@ -12,7 +12,23 @@ import (
 )
 
 type Compiler struct {
-	Context *CompilerContext
+	Context  *CompilerContext
+	Filename string
+
+	// Importing chain of modules, used to detect import cycles.
+	parent        *Compiler
+	canonical     string
+	importContext *token.Context
+
+	// Try blocks of current function, from outer to inner.
+	tryBlocks []tryBlock
+
+	// Loops of current function, from outer to inner.
+	loops []loopBlock
+
+	// Warnings do not stop compiling, those of imported modules are collected
+	// by the root compiler.
+	Warnings []*SematicError
 }
 
 func NewCompiler() *Compiler {
@@ -39,6 +55,7 @@ func (c *Compiler) CompileFile(filename string) (*opcode.CodeBlock, error) {
 }
 
 func (c *Compiler) CompileCode(filename string, code []byte) (*opcode.CodeBlock, error) {
+	c.Filename = filename
 	scanner := lex.NewRecursiveScanner(filename)
 	scanner.SetContent(code)
 
@@ -94,18 +111,25 @@ CompileSwitch:
 		}
 
 	case *ast.LetStatement:
+		if n.Pattern != nil {
+			e = r.Append(c.compileLetPattern(n))
+			break CompileSwitch
+		}
+
+		define := c.Context.Variable.DefineVariable
+		if n.IsVar() {
+			define = c.Context.Variable.DefineMutableVariable
+		}
+
 		index := make([]int, n.Identifiers.Length())
+		names := make([]string, n.Identifiers.Length())
 		for i, item := range n.Identifiers.Identifiers {
 			v := item.Identifier
-			j, ok := c.Context.Variable.DefineVariable(v.Value, v.Context)
-			if !ok {
-				ctx1, _ := c.Context.Variable.Reference(v.Value)
-				ctx2 := v.Context.Tokens[0]
-
-				e = NewSemanticError(ctx2.ToContext(), "variable %s redeclared", v.Value).
-					WithInfo(ctx1.Context,
-						"variable %s is already declared here", v.Value)
+			names[i] = v.Value
 
+			j, ok := define(v.Value, v.Context)
+			if !ok {
+				e = c.makeRedeclaredError(v.Value, v.Context.Tokens[0].ToContext())
 				break CompileSwitch
 			}
 			index[i] = j
@@ -126,7 +150,12 @@ CompileSwitch:
 
 			for i := varCount - 1; i >= 0; i-- {
 				if i < valCount {
-					r.IL(ctx, opcode.ISStore, index[i])
+					c.compileVariableStore(n, names[i], index[i], r)
+
+				} else if n.IsVar() {
+					// Cells of mutable variables are always created.
+					r.IL(ctx, opcode.ILoadNull)
+					c.compileVariableStore(n, names[i], index[i], r)
 				}
 			}
 
@@ -136,42 +165,134 @@ CompileSwitch:
 			r.IL(ctx, opcode.IStackRev)
 
 			for i := 0; i < len(index); i++ {
-				r.IL(ctx, opcode.ISStore, index[i])
+				c.compileVariableStore(n, names[i], index[i], r)
 			}
 
 			r.IL(ctx, opcode.IScopeOut, 0)
 		}
 
 	case *ast.IfStatement:
-		if e = r.Append(c.compileIfExpression(n.Expression)); e != nil {
+		if e = r.Append(c.compileIfExpression(n.Expression, flag)); e != nil {
 			break CompileSwitch
 		}
 
 	case *ast.ExpressionStatement:
+		if flag.Has(FlagTailCall) && n.Expressions.Length() == 1 {
+			nextFlag.Set(FlagTailCall)
+		}
+
 		if e = r.Append(c.compileExpression(n.Expressions, nextFlag)); e != nil {
 			break CompileSwitch
 		}
 
 	case *ast.BlockStatement:
-		if e = r.Append(c.compileStatements(n.GetContext(), n.Statements, NewFlag(FlagNone))); e != nil {
+		blockFlag := NewFlag(FlagNone)
+		if flag.Has(FlagTailCall) {
+			blockFlag.Set(FlagTailCall)
+		}
+
+		if e = r.Append(c.compileStatements(n.GetContext(), n.Statements, blockFlag)); e != nil {
 			break CompileSwitch
 		}
 
 	case *ast.ReturnStatement:
-		if e = r.Append(c.compileExpression(n.Expressions, NewFlag(FlagNone))); e != nil {
+		// Call in try block is not a tail call, handlers must be kept.
+		returnFlag := NewFlag(FlagNone)
+		if n.Expressions.Length() == 1 && len(c.tryBlocks) == 0 &&
+			c.Context.Variable.InFunctionLiteral() {
+			returnFlag.Set(FlagTailCall)
+		}
+
+		if e = r.Append(c.compileExpression(n.Expressions, returnFlag)); e != nil {
+			break CompileSwitch
+		}
+
+		if e = r.Append(c.compileLeaveTryBlocks(ctx)); e != nil {
 			break CompileSwitch
 		}
 
 		r.IL(ctx, opcode.IReturn)
+
+	case *ast.ImportStatement:
+		if e = r.Append(c.compileImportStatement(n)); e != nil {
+			break CompileSwitch
+		}
+
+	case *ast.ThrowStatement:
+		if e = r.Append(c.compileThrowStatement(n)); e != nil {
+			break CompileSwitch
+		}
+
+	case *ast.TryStatement:
+		if e = r.Append(c.compileTryStatement(n)); e != nil {
+			break CompileSwitch
+		}
+
+	case *ast.WhileStatement:
+		if e = r.Append(c.compileWhileStatement(n)); e != nil {
+			break CompileSwitch
+		}
+
+	case *ast.ForStatement:
+		if e = r.Append(c.compileForStatement(n)); e != nil {
+			break CompileSwitch
+		}
+
+	case *ast.BreakStatement:
+		if e = r.Append(c.compileLoopControl(ctx, "break", opcode.IBreak)); e != nil {
+			break CompileSwitch
+		}
+
+	case *ast.ContinueStatement:
+		if e = r.Append(c.compileLoopControl(ctx, "continue", opcode.IContinue)); e != nil {
+			break CompileSwitch
+		}
 	}
 
 	return r, e
 }
 
+// warn records a warning to the root compiler.
+func (c *Compiler) warn(w *SematicError) {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	root.Warnings = append(root.Warnings, w)
+}
+
+// checkLocalName rejects names of module and global variables, which can not be
+// parameters or variables bound by loops, catch blocks and patterns.
+func (c *Compiler) checkLocalName(name string, ctx *token.Context) error {
+	if scope := NameScope(name); scope != 0 {
+		return NewSemanticError(ctx, "%s variable %s can not be defined here", scope, name)
+	}
+
+	return nil
+}
+
+func (c *Compiler) makeRedeclaredError(name string, ctx *token.Context) *SematicError {
+	declared, _ := c.Context.Variable.Reference(name)
+	e := NewSemanticError(ctx, "variable %s redeclared", name).
+		WithInfo(declared.Context, "variable %s is already declared here", name)
+
+	return e
+}
+
 func (c *Compiler) compileExpression(expr ast.Expression, flag CompilerFlag) (*opcode.CodeBlock, error) {
 	r := opcode.NewCodeBlock()
 	var e error
 
+	// Only calls, if expressions, match expressions, single expression lists and
+	// right operands of logical expressions pass tail position on, operands of
+	// other expressions are never in tail position.
+	tailFlag := NewFlag(FlagNone)
+	if flag.Has(FlagTailCall) {
+		tailFlag.Set(FlagTailCall)
+		flag.Clear(FlagTailCall)
+	}
+
 	ctx := expr.GetContext()
 CompileSwitch:
 	switch n := expr.(type) {
@@ -192,11 +313,21 @@ CompileSwitch:
 		r.IL(ctx, opcode.ILoadInt, int(n.Value)).
 			SetValues(1)
 
+	case *ast.FloatLiteral:
+		i := c.Context.Literal.ReferenceFloat(n.Value)
+		r.IL(ctx, opcode.ILoad, int(i)).
+			SetValues(1)
+
 	case *ast.StringLiteral:
 		i := c.Context.Literal.ReferenceString(n.Value)
 		r.IL(ctx, opcode.ILoad, int(i)).
 			SetValues(1)
 
+	case *ast.InterpolatedString:
+		if e = r.Append(c.compileInterpolatedString(n)); e != nil {
+			break CompileSwitch
+		}
+
 	case *ast.ArrayLiteral:
 		f := flag
 		if n.Length() > 1 {
@@ -235,6 +366,10 @@ CompileSwitch:
 			break CompileSwitch
 		}
 
+		if info, _ := c.Context.Variable.Reference(n.Value); info.InCell() {
+			r.IL(ctx, opcode.IDeref)
+		}
+
 		r.SetValues(1)
 
 	case *ast.FunctionLiteral:
@@ -249,6 +384,10 @@ CompileSwitch:
 			f.Set(FlagPackValue)
 		}
 
+		if !isList {
+			f |= tailFlag
+		}
+
 		for _, expr := range n.Expressions {
 			if e = r.Append(c.compileExpression(expr.Expression, f)); e != nil {
 				break CompileSwitch
@@ -261,6 +400,12 @@ CompileSwitch:
 		}
 
 	case *ast.InfixExpression:
+		switch n.Operator.Token {
+		case token.AND, token.OR:
+			e = r.Append(c.compileLogicalExpression(n, flag|tailFlag))
+			break CompileSwitch
+		}
+
 		if e = r.Append(c.compileExpression(n.LeftOperand, flag.With(FlagPackValue))); e != nil {
 			break CompileSwitch
 		}
@@ -281,7 +426,12 @@ CompileSwitch:
 			SetValues(1)
 
 	case *ast.IfExpression:
-		if e = r.Append(c.compileIfExpression(n)); e != nil {
+		if e = r.Append(c.compileIfExpression(n, flag|tailFlag)); e != nil {
+			break CompileSwitch
+		}
+
+	case *ast.MatchExpression:
+		if e = r.Append(c.compileMatchExpression(n, tailFlag)); e != nil {
 			break CompileSwitch
 		}
 
@@ -290,8 +440,18 @@ CompileSwitch:
 			break CompileSwitch
 		}
 
+	case *ast.SliceExpression:
+		if e = r.Append(c.compileSliceExpression(n)); e != nil {
+			break CompileSwitch
+		}
+
+	case *ast.AssignExpression:
+		if e = r.Append(c.compileAssignExpression(n)); e != nil {
+			break CompileSwitch
+		}
+
 	case *ast.CallExpression:
-		if e = r.Append(c.compileCallExpression(n, flag)); e != nil {
+		if e = r.Append(c.compileCallExpression(n, flag|tailFlag)); e != nil {
 			break CompileSwitch
 		}
 
@@ -301,12 +461,40 @@ CompileSwitch:
 	return r, e
 }
 
+// compileInterpolatedString pushes values of all parts, and concatenates them.
+// Embedded expression with multiple values is taken the last one.
+func (c *Compiler) compileInterpolatedString(n *ast.InterpolatedString) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+	for _, part := range n.Parts {
+		value, err := c.compileExpression(part, NewFlag(FlagPackValue))
+		if err != nil {
+			return nil, err
+		}
+
+		if value.Determined && value.Values == 1 {
+			r.Block(value)
+
+		} else {
+			ctx := part.GetContext()
+			r.IL(ctx, opcode.IScopeIn)
+			r.Block(value)
+			r.IL(ctx, opcode.IScopeOut, 1)
+		}
+	}
+
+	r.IL(n.GetContext(), opcode.IConcat, len(n.Parts))
+	return r.CleanStack().SetValues(1), nil
+}
+
 func (c *Compiler) compileIdentifierReference(name string, ctx *token.Context, r *opcode.CodeBlock) int {
 	ref, kind := c.Context.Variable.Reference(name)
 	n := 1
 	switch kind {
-	case VariableKindGlobal, VariableKindModule:
-		r.IL(ctx, opcode.ILoad, ref.Offset)
+	case VariableKindGlobal:
+		r.IL(ctx, opcode.IGLoad, ref.Offset)
+
+	case VariableKindModule:
+		r.IL(ctx, opcode.IMLoad, ref.Offset)
 
 	case VariableKindBinding:
 		r.IL(ctx, opcode.ILoadBind, ref.Offset)
@@ -315,13 +503,17 @@ func (c *Compiler) compileIdentifierReference(name string, ctx *token.Context, r
 		r.IL(ctx, opcode.ISLoad, ref.Offset)
 
 	default:
-		n = 0
+		if i, ok := c.Context.Natives.Lookup(name); ok {
+			r.IL(ctx, opcode.INative, i)
+		} else {
+			n = 0
+		}
 	}
 
 	return n
 }
 
-func (c *Compiler) compileIfExpression(n *ast.IfExpression) (*opcode.CodeBlock, error) {
+func (c *Compiler) compileIfExpression(n *ast.IfExpression, flag CompilerFlag) (*opcode.CodeBlock, error) {
 	code := opcode.NewCodeBlock()
 	code.IL(n.GetContext(), opcode.IScopeIn)
 	if err := code.Append(c.compileExpression(n.Condition, NewFlag(FlagNone))); err != nil {
@@ -330,8 +522,14 @@ func (c *Compiler) compileIfExpression(n *ast.IfExpression) (*opcode.CodeBlock,
 	code.IL(n.Condition.GetContext(), opcode.IScopeOut, 1)
 	code.SetValues(0)
 
+	// Branches are in tail position if the if expression is.
+	branchFlag := NewFlag(FlagNone)
+	if flag.Has(FlagTailCall) {
+		branchFlag.Set(FlagTailCall)
+	}
+
 	c.Context.Variable.EnterScope(FrameScopeBlock)
-	consequence, err := c.compileStatement(n.Consequence, NewFlag(FlagNone))
+	consequence, err := c.compileStatement(n.Consequence, branchFlag)
 	if err != nil {
 		return nil, err
 	}
@@ -340,7 +538,7 @@ func (c *Compiler) compileIfExpression(n *ast.IfExpression) (*opcode.CodeBlock,
 	var alternative *opcode.CodeBlock
 	c.Context.Variable.EnterScope(FrameScopeBlock)
 	if n.Alternative != nil {
-		alternative, err = c.compileStatement(n.Alternative, NewFlag(FlagNone))
+		alternative, err = c.compileStatement(n.Alternative, branchFlag)
 		if err != nil {
 			return nil, err
 		}
@@ -368,6 +566,50 @@ func (c *Compiler) compileIfExpression(n *ast.IfExpression) (*opcode.CodeBlock,
 	return code, nil
 }
 
+// compileLogicalExpression compiles && and || with short-circuit evaluation.
+// The value of expression is the operand which decides the result, and right
+// operand is not evaluated if the left one decides.
+//
+//	a && b                      a || b
+//	    <a>                         <a>
+//	    SDUP                        SDUP
+//	    JUMPIF  L                   JUMPIF  1
+//	    POP     1                   JUMPFWD L
+//	    <b>                         POP     1
+//	L:                              <b>
+//	                            L:
+func (c *Compiler) compileLogicalExpression(n *ast.InfixExpression, flag CompilerFlag) (*opcode.CodeBlock, error) {
+	// Value of right operand is value of the expression, so it is in tail
+	// position if the expression is.
+	rightFlag := flag.With(FlagPackValue)
+	flag.Clear(FlagTailCall)
+
+	code := opcode.NewCodeBlock()
+	if err := code.Append(c.compileExpression(n.LeftOperand, flag.With(FlagPackValue))); err != nil {
+		return nil, err
+	}
+
+	right := opcode.NewCodeBlock()
+	right.IL(n.Operator.ToContext(), opcode.IPop, 1)
+	if err := right.Append(c.compileExpression(n.RightOperand, rightFlag)); err != nil {
+		return nil, err
+	}
+
+	ctx := n.Operator.ToContext()
+	code.IL(ctx, opcode.ISDUP)
+	if n.Operator.Token == token.AND {
+		code.IL(ctx, opcode.IJumpIf, right.Length())
+
+	} else {
+		code.IL(ctx, opcode.IJumpIf, 1)
+		code.IL(ctx, opcode.IJumpFWD, right.Length())
+	}
+
+	code.Block(right)
+	code.SetValues(1)
+	return code, nil
+}
+
 func (c *Compiler) compileEmptyStatementBlock(ctx *token.Context, flag CompilerFlag) (*opcode.CodeBlock, error) {
 	r := opcode.NewCodeBlock()
 	r.IL(ctx, opcode.ILoadNull)
@@ -410,6 +652,10 @@ func (c *Compiler) compileStatements(ctx *token.Context, statements []ast.Statem
 		}
 	}
 
+	if e != nil {
+		return nil, e
+	}
+
 	lastContext := last.GetContext()
 	nextFlag := NewFlag()
 	count := r.Values
@@ -419,6 +665,13 @@ func (c *Compiler) compileStatements(ctx *token.Context, statements []ast.Statem
 		r.CleanStack()
 	}
 
+	// Value of last statement is returned by function, or is the value of a
+	// block in tail position.
+	if flag.Has(FlagTailCall) ||
+		(flag.Has(FlagWithReturn) && c.Context.Variable.InFunctionLiteral()) {
+		nextFlag.Set(FlagTailCall)
+	}
+
 	_, isLastReturn := last.(*ast.ReturnStatement)
 	lastResult, err := c.compileStatement(last, nextFlag)
 	if err != nil {
@@ -441,10 +694,33 @@ func (c *Compiler) compileFunctionLiteral(f *ast.FunctionLiteral) (*opcode.CodeB
 	result := opcode.NewCodeBlock()
 	c.Context.Variable.EnterScope(FrameScopeFunction)
 
+	// Try blocks and loops of outer function are not left by return, break or
+	// continue in this function.
+	outerTryBlocks, outerLoops := c.tryBlocks, c.loops
+	c.tryBlocks, c.loops = nil, nil
+	defer func() {
+		c.tryBlocks, c.loops = outerTryBlocks, outerLoops
+	}()
+
 	for _, item := range f.Arguments.Identifiers {
+		if err := c.checkLocalName(item.Identifier.Value, item.Identifier.GetContext()); err != nil {
+			return nil, err
+		}
+
 		c.Context.Variable.DefineArgument(item.Identifier.Value, item.Identifier.GetContext())
 	}
 
+	// Rest parameter is the last argument, collecting extra arguments.
+	arguments := f.Arguments.Length()
+	if f.IsVariadic() {
+		if err := c.checkLocalName(f.Rest.Value, f.Rest.GetContext()); err != nil {
+			return nil, err
+		}
+
+		c.Context.Variable.DefineArgument(f.Rest.Value, f.Rest.GetContext())
+		arguments++
+	}
+
 	r, e := c.compileStatements(f.Body.GetContext(), f.Body.Statements, NewFlag(FlagWithReturn))
 	if e != nil {
 		return nil, e
@@ -453,11 +729,12 @@ func (c *Compiler) compileFunctionLiteral(f *ast.FunctionLiteral) (*opcode.CodeB
 	frameSize := c.Context.Variable.CurrentScope().UpdateFrameSize(0)
 	functionContext := &opcode.Function{
 		FrameSize: frameSize,
-		Arguments: f.Arguments.Length(),
+		Arguments: arguments,
+		Variadic:  f.IsVariadic(),
 		Codes:     r,
 	}
 
-	id := c.Context.AddFunction(functionContext)
+	c.Context.AddFunction(functionContext)
 	scope := c.Context.Variable.CurrentScope()
 	c.Context.Variable.LeaveScope()
 
@@ -465,26 +742,60 @@ func (c *Compiler) compileFunctionLiteral(f *ast.FunctionLiteral) (*opcode.CodeB
 		c.compileIdentifierReference(arg.Name, arg.Context, result)
 	}
 
-	result.IL(f.GetContext(), opcode.IMakeFunc, id, len(scope.Bindings))
+	result.IL(f.GetContext(), opcode.IMakeFunc, functionContext, len(scope.Bindings))
 	result.Values = 1
 	return result, nil
 }
 
 func (c *Compiler) compileCallExpression(expr *ast.CallExpression, flag CompilerFlag) (*opcode.CodeBlock, error) {
 	result := opcode.NewCodeBlock()
+	tail := flag.Has(FlagTailCall)
+
+	// Arguments with spread are counted at runtime, all values in the scope
+	// are passed to CALLV.
+	spread := expr.Args.HasSpread()
+	if spread {
+		result.IL(expr.GetContext(), opcode.IScopeIn)
+	}
 
 	args := opcode.NewCodeBlock()
 	l := expr.Args.Length()
 	for i := 0; i < l; i++ {
 		a := expr.Args.Expressions[l-i-1]
+		if s, ok := a.Expression.(*ast.SpreadExpression); ok {
+			if e := args.Append(c.compileExpression(s.Expression, NewFlag(FlagPackValue))); e != nil {
+				return nil, e
+			}
+			args.IL(s.GetContext(), opcode.ISpread)
+			continue
+		}
+
 		if e := args.Append(c.compileExpression(a.Expression, NewFlag(FlagPackValue))); e != nil {
 			return nil, e
 		}
 	}
 	result.Block(args)
+	argc := args.Values
+
+	// Callable itself is never in tail position.
+	flag.Clear(FlagTailCall)
 
 	switch expr.Token.GetToken() {
 	case token.Nil:
+		if f, ok := c.getBuiltinFunction(expr.Base); ok {
+			if spread {
+				return nil, NewSemanticError(expr.GetContext(),
+					"spread arguments are not accepted by %s", expr.Base.CanonicalCode())
+			}
+
+			if err := result.Append(c.compileBuiltinCall(expr, f)); err != nil {
+				return nil, err
+			}
+
+			result.Values = 1
+			return result, nil
+		}
+
 		callable, err := c.compileExpression(expr.Base, flag)
 		if err != nil {
 			return nil, err
@@ -492,27 +803,56 @@ func (c *Compiler) compileCallExpression(expr *ast.CallExpression, flag Compiler
 		result.Block(callable)
 
 	case token.DualColon:
-		callable, err := c.compileExpression(expr.Base, flag)
+		// Receiver is the first argument, and the method is looked up from it.
+		self, err := c.compileExpression(expr.Base, NewFlag(FlagPackValue))
 		if err != nil {
 			return nil, err
 		}
 
 		memberIndex := c.Context.Literal.ReferenceString(expr.Member.Value)
-		callable.IL(expr.Member.GetContext(), opcode.ILoad, int(memberIndex))
-		callable.IL(expr.Base.GetContext(), opcode.ISDUP)
-		callable.IL(expr.Member.GetContext(), opcode.IIndex)
-		result.Block(callable)
+		self.IL(expr.Member.GetContext(), opcode.IMethod, int(memberIndex))
+		result.Block(self)
+		argc++
 
 	case token.Fn:
 		result.IL(expr.Token.ToContext(), opcode.ISLoad, 0)
 	}
 
-	result.IL(expr.GetContext(), opcode.ICall, args.Values)
+	if spread {
+		call := opcode.ICallV
+		if tail {
+			call = opcode.ITailCallV
+		}
+
+		result.IL(expr.GetContext(), call)
+		result.Values = 1
+		return result, nil
+	}
+
+	call := opcode.ICall
+	if tail {
+		call = opcode.ITailCall
+	}
+
+	result.IL(expr.GetContext(), call, argc)
 	result.Values = 1
 	return result, nil
 }
 
 func (c *Compiler) compileIndexExpression(expr *ast.IndexExpression) (*opcode.CodeBlock, error) {
+	result, err := c.compileIndexOperands(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	result.IL(expr.Index.GetContext(), opcode.IIndex).
+		SetValues(1)
+	return result, nil
+}
+
+// compileIndexOperands pushes base and index of index expression, key of
+// period notation is a string.
+func (c *Compiler) compileIndexOperands(expr *ast.IndexExpression) (*opcode.CodeBlock, error) {
 	result := opcode.NewCodeBlock()
 
 	base, err := c.compileExpression(expr.Base, NewFlag(FlagPackValue))
@@ -537,8 +877,30 @@ func (c *Compiler) compileIndexExpression(expr *ast.IndexExpression) (*opcode.Co
 
 	result.Block(base)
 	result.Block(index)
-	result.IL(expr.Index.GetContext(), opcode.IIndex)
+	return result, nil
+}
+
+// compileSliceExpression pushes base and bounds of slice, omitted bound is null.
+func (c *Compiler) compileSliceExpression(expr *ast.SliceExpression) (*opcode.CodeBlock, error) {
+	result := opcode.NewCodeBlock()
+
+	if err := result.Append(c.compileExpression(expr.Base, NewFlag(FlagPackValue))); err != nil {
+		return nil, err
+	}
+
+	for _, bound := range []ast.Expression{expr.Low, expr.High} {
+		if bound == nil {
+			result.IL(expr.Colon.ToContext(), opcode.ILoadNull)
+			continue
+		}
+
+		if err := result.Append(c.compileExpression(bound, NewFlag(FlagPackValue))); err != nil {
+			return nil, err
+		}
+	}
 
+	result.IL(expr.GetContext(), opcode.ISlice)
+	result.SetValues(1)
 	return result, nil
 }
 
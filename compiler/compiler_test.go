@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/flily/macaque-lang/ast"
 	"github.com/flily/macaque-lang/lex"
 	"github.com/flily/macaque-lang/object"
 	"github.com/flily/macaque-lang/opcode"
@@ -43,7 +44,7 @@ func makeCodePrint(left []opcode.Opcode, right []opcode.Opcode) string {
 	return strings.Join(parts, "\n")
 }
 
-func testCompileCode(t *testing.T, code string) (*Compiler, *opcode.CodePage, error) {
+func parseCode(t *testing.T, code string) *ast.Program {
 	t.Helper()
 
 	scanner := lex.NewRecursiveScanner("testcase")
@@ -59,6 +60,13 @@ func testCompileCode(t *testing.T, code string) (*Compiler, *opcode.CodePage, er
 		t.Fatalf("parser error:\n%s", err)
 	}
 
+	return program
+}
+
+func testCompileCode(t *testing.T, code string) (*Compiler, *opcode.CodePage, error) {
+	t.Helper()
+
+	program := parseCode(t, code)
 	compiler := NewCompiler()
 	block, err := compiler.CompileASTSnippet(program)
 	if err != nil {
@@ -53,13 +53,38 @@ func (k VarKind) String() string {
 	return "unknown"
 }
 
+// NameScope returns the scope where a variable is defined by its name prefix,
+// FrameScopeModule for '@' and FrameScopeGlobal for '$'. Zero is returned for
+// names without prefix, which are defined in current scope.
+func NameScope(name string) FrameScope {
+	if len(name) > 0 {
+		switch name[0] {
+		case '@':
+			return FrameScopeModule
+
+		case '$':
+			return FrameScopeGlobal
+		}
+	}
+
+	return 0
+}
+
 type VariableInfo struct {
 	Name    string
 	Offset  int
 	Kind    VarKind
+	Mutable bool // declared by var
 	Context *token.Context
 }
 
+// InCell checks whether value of variable is kept in a cell. Mutable local
+// variables are, so closures share them, while module and global variables are
+// stored in data areas of VM directly.
+func (v VariableInfo) InCell() bool {
+	return v.Mutable && v.Kind != VariableKindGlobal && v.Kind != VariableKindModule
+}
+
 type VariableScopeContext struct {
 	Level        int
 	outer        *VariableScopeContext
@@ -123,23 +148,49 @@ func (c *VariableScopeContext) DefineArgument(name string, ctx *token.Context) (
 	return n, true
 }
 
+// DefineVariable defines a variable in current scope, or in module or global
+// scope if the name has prefix '@' or '$'. Variables of module and global scope
+// are indexed from zero in their data areas, rather than slots in frame.
 func (c *VariableScopeContext) DefineVariable(name string, ctx *token.Context) (int, bool) {
+	if scope := NameScope(name); scope != 0 && scope != c.Scope {
+		return c.lookupScope(scope).DefineVariable(name, ctx)
+	}
+
 	if c.IsDefined(name) {
 		return 0, false
 	}
 
 	n := c.FrameOffset() + 1
+	if c.Scope == FrameScopeGlobal || c.Scope == FrameScopeModule {
+		n = c.variables
+	}
+
 	c.variables += 1
 	c.Variables[name] = VariableInfo{
 		Name:    name,
 		Offset:  n,
-		Kind:    VariableKindLocal,
+		Kind:    c.currentVariableKind(),
 		Context: ctx,
 	}
 
 	return n, true
 }
 
+func (c *VariableScopeContext) DefineMutableVariable(name string, ctx *token.Context) (int, bool) {
+	if scope := NameScope(name); scope != 0 && scope != c.Scope {
+		return c.lookupScope(scope).DefineMutableVariable(name, ctx)
+	}
+
+	n, ok := c.DefineVariable(name, ctx)
+	if ok {
+		info := c.Variables[name]
+		info.Mutable = true
+		c.Variables[name] = info
+	}
+
+	return n, ok
+}
+
 func (c *VariableScopeContext) AddBinding(name string, info VariableInfo) VariableInfo {
 	if v, ok := c.Bindings[name]; ok {
 		return v
@@ -167,7 +218,30 @@ func (c *VariableScopeContext) currentVariableKind() VarKind {
 	return 0
 }
 
+// lookupScope returns the nearest scope of given type, from current scope to
+// the root.
+func (c *VariableScopeContext) lookupScope(scope FrameScope) *VariableScopeContext {
+	s := c
+	for s.Scope != scope && !s.IsRoot() {
+		s = s.outer
+	}
+
+	return s
+}
+
+// Reference looks up a variable from current scope to the root. Variables with
+// prefix '@' or '$' are looked up in module or global scope directly, and never
+// bound to closures.
 func (c *VariableScopeContext) Reference(name string) (VariableInfo, VarKind) {
+	if scope := NameScope(name); scope != 0 {
+		s := c.lookupScope(scope)
+		if v, ok := s.Variables[name]; ok {
+			return v, s.currentVariableKind()
+		}
+
+		return VariableInfo{}, VariableKindMiss
+	}
+
 	v, ok := c.Variables[name]
 	if ok {
 		return v, c.currentVariableKind()
@@ -184,8 +258,11 @@ func (c *VariableScopeContext) Reference(name string) (VariableInfo, VarKind) {
 
 	info, kind := c.outer.Reference(name)
 	if kind != VariableKindLocal {
-		if kind == VariableKindBinding {
-			c.AddBinding(name, info)
+		// A binding of outer function is bound again to this function, with
+		// offset in bindings of this function. Blocks share bindings of the
+		// function they belong to.
+		if kind == VariableKindBinding && c.Scope == FrameScopeFunction {
+			return c.AddBinding(name, info), kind
 		}
 
 		return info, kind
@@ -219,10 +296,11 @@ func (c *VariableScopeContext) UpdateFrameSize(count int) int {
 		r = c.outer.UpdateFrameSize(n)
 
 	case FrameScopeFunction:
-		r = n
+		// Variables in left block scopes still take slots in the frame.
 		if n > c.FrameSize {
 			c.FrameSize = n
 		}
+		r = c.FrameSize
 	}
 
 	return r
@@ -250,6 +328,18 @@ func NewVariableContext() *VariableContext {
 	return c
 }
 
+// newModuleVariableContext creates variable context of an imported module, with
+// a module scope of its own and the global scope shared with parent.
+func newModuleVariableContext(parent *VariableContext) *VariableContext {
+	c := &VariableContext{
+		root: parent.root,
+		top:  parent.root,
+	}
+
+	c.EnterScope(FrameScopeModule)
+	return c
+}
+
 func (c *VariableContext) CurrentScope() *VariableScopeContext {
 	return c.top
 }
@@ -270,11 +360,27 @@ func (c *VariableContext) DefineVariable(name string, pos *token.Context) (int,
 	return c.top.DefineVariable(name, pos)
 }
 
+func (c *VariableContext) DefineMutableVariable(name string, pos *token.Context) (int, bool) {
+	return c.top.DefineMutableVariable(name, pos)
+}
+
 func (c *VariableContext) Reference(name string) (VariableInfo, VarKind) {
 	info, kind := c.top.Reference(name)
 	return info, kind
 }
 
+// InFunctionLiteral checks whether current scope is in a function literal,
+// rather than the main function of a module.
+func (c *VariableContext) InFunctionLiteral() bool {
+	s := c.top
+	for s != nil && s.Scope == FrameScopeBlock {
+		s = s.outer
+	}
+
+	return s != nil && s.Scope == FrameScopeFunction &&
+		s.outer != nil && s.outer.Scope != FrameScopeModule
+}
+
 func (c *VariableContext) CurrentFrameSize() int {
 	return c.top.UpdateFrameSize(0)
 }
@@ -320,9 +426,20 @@ func (c *LiteralContext) ReferenceString(s string) uint64 {
 	return c.Add(s, o)
 }
 
+func (c *LiteralContext) ReferenceFloat(f float64) uint64 {
+	if n, ok := c.Lookup(f); ok {
+		return n
+	}
+
+	o := object.NewFloat(f)
+	return c.Add(f, o)
+}
+
 type CompilerContext struct {
 	Variable  *VariableContext
 	Literal   *LiteralContext
+	Modules   *ModuleContext
+	Natives   *NativeContext
 	Functions []*opcode.Function
 }
 
@@ -344,14 +461,36 @@ func (c *CompilerContext) LinkCodePage(main *opcode.CodeBlock) *opcode.CodePage
 	data := make([]object.Object, len(c.Literal.Values))
 	copy(data, c.Literal.Values)
 
-	page := &opcode.CodePage{
-		Functions: links,
-		Data:      data,
+	page := opcode.NewCodePage()
+	page.Functions = links
+	page.Data = data
+	page.Natives = make([]string, len(c.Natives.Names))
+	copy(page.Natives, c.Natives.Names)
+	for _, m := range c.Modules.Modules {
+		page.AddModule(m)
 	}
 
+	page.LinkFunctions()
 	return page
 }
 
+// LinkModule makes an imported module, with main function of module.
+func (c *CompilerContext) LinkModule(name string, canonical string, main *opcode.CodeBlock) *opcode.Module {
+	c.Functions[0] = &opcode.Function{
+		FrameSize: c.Variable.CurrentFrameSize(),
+		Codes:     main,
+	}
+
+	m := &opcode.Module{
+		Name:      name,
+		Canonical: canonical,
+		Type:      opcode.ModuleTypeImported,
+		Functions: c.Functions,
+	}
+
+	return m
+}
+
 func (c *CompilerContext) AddFunction(f *opcode.Function) int {
 	n := len(c.Functions)
 	f.GlobalIndex = uint64(n)
@@ -364,6 +503,8 @@ func NewCompilerContext() *CompilerContext {
 	c := &CompilerContext{
 		Variable: NewVariableContext(),
 		Literal:  NewLiteralContext(),
+		Modules:  NewModuleContext(),
+		Natives:  NewNativeContext(),
 		Functions: []*opcode.Function{
 			nil, // reserve for main function
 		},
@@ -371,3 +512,17 @@ func NewCompilerContext() *CompilerContext {
 
 	return c
 }
+
+func newModuleCompilerContext(parent *CompilerContext) *CompilerContext {
+	c := &CompilerContext{
+		Variable: newModuleVariableContext(parent.Variable),
+		Literal:  parent.Literal,
+		Modules:  parent.Modules,
+		Natives:  parent.Natives,
+		Functions: []*opcode.Function{
+			nil, // reserve for main function of module
+		},
+	}
+
+	return c
+}
@@ -1 +1,72 @@
 package compiler
+
+import (
+	"testing"
+)
+
+func TestNameScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope FrameScope
+	}{
+		{"a", 0},
+		{"empty?", 0},
+		{"push!", 0},
+		{"@a", FrameScopeModule},
+		{"$a", FrameScopeGlobal},
+		{"", 0},
+	}
+
+	for _, c := range tests {
+		if got := NameScope(c.name); got != c.scope {
+			t.Errorf("NameScope(%s) got %s, expected %s", c.name, got, c.scope)
+		}
+	}
+}
+
+func TestVariableScopeReference(t *testing.T) {
+	v := NewVariableContext()
+	v.EnterScope(FrameScopeFunction)
+	v.DefineVariable("a", nil)
+	v.DefineVariable("@a", nil)
+	v.EnterScope(FrameScopeFunction)
+	v.EnterScope(FrameScopeBlock)
+	v.DefineVariable("$a", nil)
+	v.DefineVariable("@b", nil)
+
+	tests := []struct {
+		name   string
+		offset int
+		kind   VarKind
+	}{
+		{"a", 0, VariableKindBinding},
+		{"@a", 0, VariableKindModule},
+		{"@b", 1, VariableKindModule},
+		{"$a", 0, VariableKindGlobal},
+		{"$b", 0, VariableKindMiss},
+		{"b", 0, VariableKindMiss},
+	}
+
+	for _, c := range tests {
+		info, kind := v.Reference(c.name)
+		if kind != c.kind || info.Offset != c.offset {
+			t.Errorf("Reference(%s) got %s %d, expected %s %d",
+				c.name, kind, info.Offset, c.kind, c.offset)
+		}
+	}
+
+	// Scoped variables are not bound to closures.
+	if n := len(v.CurrentScope().outer.Bindings); n != 1 {
+		t.Errorf("function has %d bindings, expected 1", n)
+	}
+
+	if _, ok := v.DefineVariable("@a", nil); ok {
+		t.Errorf("@a is redefined in module scope")
+	}
+
+	v.LeaveScope()
+	v.LeaveScope()
+	if _, kind := v.Reference("@b"); kind != VariableKindModule {
+		t.Errorf("@b is not in module scope after leaving function")
+	}
+}
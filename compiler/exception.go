@@ -0,0 +1,197 @@
+package compiler
+
+import (
+	"github.com/flily/macaque-lang/ast"
+	"github.com/flily/macaque-lang/opcode"
+	"github.com/flily/macaque-lang/token"
+)
+
+// tryBlock is a try block or catch block being compiled, which a return
+// statement in it MUST leave before returning.
+type tryBlock struct {
+	handler bool                // an exception handler is installed
+	finally *ast.BlockStatement // finally block to run when leaving
+}
+
+func (c *Compiler) compileThrowStatement(n *ast.ThrowStatement) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+	if err := r.Append(c.compileExpression(n.Expression, NewFlag(FlagPackValue))); err != nil {
+		return nil, err
+	}
+
+	r.IL(n.Throw.ToContext(), opcode.IThrow)
+	r.SetValues(0)
+	return r, nil
+}
+
+// compileTryStatement compiles try statement. Value of the statement is the
+// value of try block, or the value of catch block if an exception is caught.
+// Finally block is copied to every path leaving the statement.
+//
+//	    TRY     L1                  ; catch handler
+//	    <try block>
+//	    ENDTRY
+//	    JUMPFWD L3
+//	L1: SSTORE  e                   ; exception value on stack
+//	    TRY     L2                  ; finally handler, if finally block exists
+//	    <catch block>
+//	    ENDTRY
+//	    JUMPFWD L3
+//	L2: <finally block>             ; exception thrown in catch block
+//	    THROW
+//	L3: <finally block>
+func (c *Compiler) compileTryStatement(n *ast.TryStatement) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+	ctx := n.Try.ToContext()
+	hasFinally := n.FinallyBody != nil
+
+	body, err := c.compileTryBlock(n.Body, tryBlock{true, n.FinallyBody})
+	if err != nil {
+		return nil, err
+	}
+	body.IL(ctx, opcode.IEndTry)
+
+	var rethrow *opcode.CodeBlock
+	if hasFinally {
+		if rethrow, err = c.compileFinallyBlock(n.FinallyBody); err != nil {
+			return nil, err
+		}
+		rethrow.IL(n.Finally.ToContext(), opcode.IThrow)
+	}
+
+	handler := opcode.NewCodeBlock()
+	if n.CatchBody != nil {
+		if handler, err = c.compileCatchBlock(n, rethrow); err != nil {
+			return nil, err
+		}
+	}
+
+	if rethrow != nil {
+		handler.Block(rethrow)
+	}
+
+	r.IL(ctx, opcode.ITry, body.Length()+1)
+	r.Block(body)
+	r.IL(ctx, opcode.IJumpFWD, handler.Length())
+	r.Block(handler)
+	r.SetValues(body.Values)
+
+	countBody, countHandler := body.Values, handler.Values
+	if !body.Determined || !handler.Determined ||
+		(n.CatchBody != nil && countBody != countHandler) {
+		r.Undetermined()
+	}
+
+	if hasFinally {
+		if err := r.Append(c.compileFinallyBlock(n.FinallyBody)); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (c *Compiler) compileTryBlock(block *ast.BlockStatement, t tryBlock) (*opcode.CodeBlock, error) {
+	c.tryBlocks = append(c.tryBlocks, t)
+	defer func() {
+		c.tryBlocks = c.tryBlocks[:len(c.tryBlocks)-1]
+	}()
+
+	c.Context.Variable.EnterScope(FrameScopeBlock)
+	defer c.Context.Variable.LeaveScope()
+
+	return c.compileStatement(block, NewFlag(FlagNone))
+}
+
+func (c *Compiler) compileCatchBlock(n *ast.TryStatement, rethrow *opcode.CodeBlock) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+	ctx := n.Catch.ToContext()
+
+	// Exception variable is defined in the same scope with catch block.
+	c.Context.Variable.EnterScope(FrameScopeBlock)
+	defer c.Context.Variable.LeaveScope()
+
+	name, nameContext := n.Variable.Value, n.Variable.GetContext()
+	if err := c.checkLocalName(name, nameContext); err != nil {
+		return nil, err
+	}
+
+	index, ok := c.Context.Variable.DefineVariable(name, nameContext)
+	if !ok {
+		return nil, c.makeRedeclaredError(name, nameContext)
+	}
+
+	c.tryBlocks = append(c.tryBlocks, tryBlock{rethrow != nil, n.FinallyBody})
+	body, err := c.compileStatement(n.CatchBody, NewFlag(FlagNone))
+	c.tryBlocks = c.tryBlocks[:len(c.tryBlocks)-1]
+	if err != nil {
+		return nil, err
+	}
+
+	r.IL(nameContext, opcode.ISStore, index)
+	if rethrow != nil {
+		r.IL(ctx, opcode.ITry, body.Length()+2)
+		r.Block(body)
+		r.IL(ctx, opcode.IEndTry)
+		r.IL(ctx, opcode.IJumpFWD, rethrow.Length())
+
+	} else {
+		r.Block(body)
+	}
+
+	return r, nil
+}
+
+// compileFinallyBlock compiles a copy of finally block, in its own scope, and
+// values of the block are dropped.
+func (c *Compiler) compileFinallyBlock(block *ast.BlockStatement) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+	ctx := block.LBrace.ToContext()
+
+	c.Context.Variable.EnterScope(FrameScopeBlock)
+	body, err := c.compileStatement(block, NewFlag(FlagNone))
+	c.Context.Variable.LeaveScope()
+	if err != nil {
+		return nil, err
+	}
+
+	r.IL(ctx, opcode.IScopeIn)
+	r.Block(body)
+	r.IL(ctx, opcode.IClean)
+	r.IL(ctx, opcode.IScopeOut, 0)
+	r.CleanStack()
+	return r, nil
+}
+
+// compileLeaveTryBlocks leaves all try blocks in current function, from inner
+// to outer. Exception handlers are removed and finally blocks are run.
+func (c *Compiler) compileLeaveTryBlocks(ctx *token.Context) (*opcode.CodeBlock, error) {
+	return c.compileLeaveTryBlocksTo(ctx, 0)
+}
+
+// compileLeaveTryBlocksTo leaves try blocks from inner to outer, until depth
+// try blocks are left.
+func (c *Compiler) compileLeaveTryBlocksTo(ctx *token.Context, depth int) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+
+	blocks := c.tryBlocks
+	defer func() {
+		c.tryBlocks = blocks
+	}()
+
+	for i := len(blocks) - 1; i >= depth; i-- {
+		// Return in finally block leaves outer blocks only.
+		c.tryBlocks = blocks[:i]
+		if blocks[i].handler {
+			r.IL(ctx, opcode.IEndTry)
+		}
+
+		if blocks[i].finally != nil {
+			if err := r.Append(c.compileFinallyBlock(blocks[i].finally)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return r, nil
+}
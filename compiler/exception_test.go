@@ -0,0 +1,76 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/opcode"
+)
+
+func TestCompileTryStatement(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			text(
+				`try { throw 1 } catch (e) { e }`,
+			),
+			code(
+				inst(opcode.ITry, 6),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IThrow),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IEndTry),
+				inst(opcode.IJumpFWD, 4),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IScopeIn),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.IScopeOut, 0),
+			),
+			data(),
+		},
+		{
+			text(
+				`fn(g) { try { return g(1) } finally { 2 } }`,
+			),
+			// Call in try block is not a tail call, and finally block is
+			// copied to the return path, the exception path and the end.
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ITry, 14),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISLoad, -1),
+				inst(opcode.ICall, 1),
+				inst(opcode.IEndTry),
+				inst(opcode.IScopeIn),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IClean),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IReturn),
+				inst(opcode.IEndTry),
+				inst(opcode.IJumpFWD, 7),
+				inst(opcode.IScopeIn),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IClean),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IThrow),
+				inst(opcode.IScopeIn),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IClean),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
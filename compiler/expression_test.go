@@ -201,6 +201,38 @@ func TestCompileIfExpressionError(t *testing.T) {
 	runCompilerErrorTestCases(t, tests)
 }
 
+func TestCompileLogicalExpression(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			`true && 1`,
+			code(
+				inst(opcode.ILoadBool, 1),
+				inst(opcode.ISDUP),
+				inst(opcode.IJumpIf, 2),
+				inst(opcode.IPop, 1),
+				inst(opcode.ILoadInt, 1),
+			),
+			data(),
+		},
+		{
+			`false || 1 + 2`,
+			code(
+				inst(opcode.ILoadBool, 0),
+				inst(opcode.ISDUP),
+				inst(opcode.IJumpIf, 1),
+				inst(opcode.IJumpFWD, 4),
+				inst(opcode.IPop, 1),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IBinOp, int(token.Plus)),
+			),
+			data(),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
 func TestCallExpression(t *testing.T) {
 	tests := []testCompilerCase{
 		{
@@ -226,6 +258,57 @@ func TestCallExpression(t *testing.T) {
 			),
 			data(),
 		},
+		{
+			text(
+				"let f = fn(a, ...b) { b };",
+				"f(1, ...[2])",
+			),
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IMakeList, 1),
+				inst(opcode.ISpread),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ICallV),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ISLoad, -2),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+		{
+			`5::times(1)`,
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadInt, 5),
+				inst(opcode.IMethod, 0),
+				inst(opcode.ICall, 2),
+			),
+			data(object.NewString("times")),
+		},
+		{
+			`"a"::split(...["b"])`,
+			code(
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoad, 0),
+				inst(opcode.IMakeList, 1),
+				inst(opcode.ISpread),
+				inst(opcode.ILoad, 1),
+				inst(opcode.IMethod, 2),
+				inst(opcode.ICallV),
+			),
+			data(
+				object.NewString("b"),
+				object.NewString("a"),
+				object.NewString("split"),
+			),
+		},
 	}
 
 	runCompilerTestCases(t, tests)
@@ -273,6 +356,155 @@ func TestIndexExpression(t *testing.T) {
 				object.NewString("two"),
 			),
 		},
+		{
+			text(
+				"let arr = [1, 2, 3];",
+				"arr[1:], arr[:-1]",
+			),
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoadInt, 3),
+				inst(opcode.IMakeList, 3),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadNull),
+				inst(opcode.ISlice),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ILoadNull),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IUniOp, int(token.Minus)),
+				inst(opcode.ISlice),
+			),
+			data(),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
+func TestCompileTailCall(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			text(
+				`let f = fn(g) { return g(1); };`,
+				`f(f)`,
+			),
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ICall, 1),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISLoad, -1),
+				inst(opcode.ITailCall, 1),
+				inst(opcode.IReturn),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+		{
+			text(
+				`fn(g) { 1 + g(2) }`,
+			),
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ISLoad, -1),
+				inst(opcode.ICall, 1),
+				inst(opcode.IBinOp, int(token.Plus)),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+		{
+			text(
+				`fn(g) { return 1, g(2); }`,
+			),
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ISLoad, -1),
+				inst(opcode.ICall, 1),
+				inst(opcode.IReturn),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+		{
+			text(
+				`fn(g) { g(1)(2) }`,
+			),
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISLoad, -1),
+				inst(opcode.ICall, 1),
+				inst(opcode.ITailCall, 1),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+		{
+			text(
+				`fn(g) { g(1) && g(2) }`,
+			),
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISLoad, -1),
+				inst(opcode.ICall, 1),
+				inst(opcode.ISDUP),
+				inst(opcode.IJumpIf, 4),
+				inst(opcode.IPop, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ISLoad, -1),
+				inst(opcode.ITailCall, 1),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+		{
+			text(
+				`fn(g) { g(...[1]) }`,
+			),
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IMakeList, 1),
+				inst(opcode.ISpread),
+				inst(opcode.ISLoad, -1),
+				inst(opcode.ITailCallV),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
 	}
 
 	runCompilerTestCases(t, tests)
@@ -13,6 +13,7 @@ const (
 	FlagCleanStack   = 0x0100
 	FlagWithReturn   = 0x0200
 	FlagWithoutScope = 0x0400
+	FlagTailCall     = 0x0800
 )
 
 func NewFlag(flags ...uint64) CompilerFlag {
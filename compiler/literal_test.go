@@ -3,6 +3,7 @@ package compiler
 import (
 	"testing"
 
+	"github.com/flily/macaque-lang/object"
 	"github.com/flily/macaque-lang/opcode"
 	"github.com/flily/macaque-lang/token"
 )
@@ -361,7 +362,7 @@ func TestCompileRecursiveFunctions(t *testing.T) {
 				inst(opcode.ILoadInt, 1),              // 19
 				inst(opcode.IBinOp, int(token.Minus)), // 20
 				inst(opcode.ISLoad, 0),                // 21
-				inst(opcode.ICall, 1),                 // 22
+				inst(opcode.ITailCall, 1),             // 22
 				inst(opcode.IScopeOut),                // 23
 				//    }
 				inst(opcode.IReturn), // 18
@@ -433,3 +434,90 @@ func TestCompileRecursiveFunctions(t *testing.T) {
 
 	runCompilerTestCases(t, tests)
 }
+
+func TestCompileFloatLiteral(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			text(
+				"3.25",
+			),
+			code(
+				inst(opcode.ILoad, 0),
+			),
+			data(
+				object.NewFloat(3.25),
+			),
+		},
+		{
+			text(
+				"1.5 + 2 * 1.5",
+			),
+			code(
+				inst(opcode.ILoad, 0),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoad, 0),
+				inst(opcode.IBinOp, int(token.Asterisk)),
+				inst(opcode.IBinOp, int(token.Plus)),
+			),
+			data(
+				object.NewFloat(1.5),
+			),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
+func TestCompileInterpolatedString(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			text(
+				`let a = 1;`,
+				`"a=${a}, ${[a, 2]}!"`,
+			),
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.ILoad, 0),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ILoad, 1),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IMakeList, 2),
+				inst(opcode.ILoad, 2),
+				inst(opcode.IConcat, 5),
+			),
+			data(
+				object.NewString("a="),
+				object.NewString(", "),
+				object.NewString("!"),
+			),
+		},
+		{
+			text(
+				`let f = fn() { 1, 2 };`,
+				`"${f()}"`,
+			),
+			code(
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.IScopeIn),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.ICall, 0),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IConcat, 1),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
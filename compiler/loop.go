@@ -0,0 +1,147 @@
+package compiler
+
+import (
+	"github.com/flily/macaque-lang/ast"
+	"github.com/flily/macaque-lang/opcode"
+	"github.com/flily/macaque-lang/token"
+)
+
+// loopBlock is a loop being compiled. Break and continue statements in it
+// leave try blocks entered in the loop body.
+type loopBlock struct {
+	tryBlocks int // number of try blocks outside the loop
+}
+
+// compileWhileStatement compiles while statement, which has no value.
+//
+//	L0: SCOPEIN
+//	    <condition>
+//	    SCOPEOUT 1
+//	    JUMPIF   L2
+//	    LOOPIN   L2
+//	    <body>
+//	    CONTINUE
+//	L1: JUMP     L0                 ; continue
+//	L2:                             ; break
+func (c *Compiler) compileWhileStatement(n *ast.WhileStatement) (*opcode.CodeBlock, error) {
+	r, err := c.compileLoopOperand(n.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Context.Variable.EnterScope(FrameScopeBlock)
+	body, err := c.compileLoopBody(n.Body)
+	c.Context.Variable.LeaveScope()
+	if err != nil {
+		return nil, err
+	}
+
+	r.IL(n.Condition.GetContext(), opcode.IJumpIf, body.Length()+1)
+	r.Block(body)
+	r.IL(n.While.ToContext(), opcode.IJump, -(r.Length() + 1))
+	r.CleanStack()
+	return r, nil
+}
+
+// compileForStatement compiles for-in statement, which has no value. The
+// iterator is kept on the stack until the loop finishes.
+//
+//	    <iterable>
+//	    ITER
+//	L0: NEXT     L2
+//	    SSTORE   x
+//	    LOOPIN   L2
+//	    <body>
+//	    CONTINUE
+//	L1: JUMP     L0                 ; continue
+//	L2: POP      1                  ; break
+func (c *Compiler) compileForStatement(n *ast.ForStatement) (*opcode.CodeBlock, error) {
+	ctx := n.For.ToContext()
+	r, err := c.compileLoopOperand(n.Iterable)
+	if err != nil {
+		return nil, err
+	}
+	r.IL(n.Iterable.GetContext(), opcode.IIter)
+
+	// Loop variable is defined in the same scope with loop body.
+	c.Context.Variable.EnterScope(FrameScopeBlock)
+	defer c.Context.Variable.LeaveScope()
+
+	name, nameContext := n.Variable.Value, n.Variable.GetContext()
+	if err := c.checkLocalName(name, nameContext); err != nil {
+		return nil, err
+	}
+
+	index, ok := c.Context.Variable.DefineVariable(name, nameContext)
+	if !ok {
+		return nil, c.makeRedeclaredError(name, nameContext)
+	}
+
+	body, err := c.compileLoopBody(n.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	loop := opcode.NewCodeBlock()
+	loop.IL(n.In.ToContext(), opcode.INext, body.Length()+2)
+	loop.IL(nameContext, opcode.ISStore, index)
+	loop.Block(body)
+	loop.IL(ctx, opcode.IJump, -(loop.Length() + 1))
+
+	r.Block(loop)
+	r.IL(ctx, opcode.IPop, 1)
+	r.CleanStack()
+	return r, nil
+}
+
+// compileLoopOperand compiles condition of while or iterable of for, exactly
+// one value is left on the stack.
+func (c *Compiler) compileLoopOperand(expr ast.Expression) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+	ctx := expr.GetContext()
+
+	r.IL(ctx, opcode.IScopeIn)
+	if err := r.Append(c.compileExpression(expr, NewFlag(FlagNone))); err != nil {
+		return nil, err
+	}
+	r.IL(ctx, opcode.IScopeOut, 1)
+	r.SetValues(1)
+	return r, nil
+}
+
+// compileLoopBody compiles body of loop in a loop scope, values of the body
+// are dropped by CONTINUE at the end.
+func (c *Compiler) compileLoopBody(block *ast.BlockStatement) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+
+	c.loops = append(c.loops, loopBlock{len(c.tryBlocks)})
+	body, err := c.compileStatements(block.GetContext(), block.Statements, NewFlag(FlagWithoutScope))
+	c.loops = c.loops[:len(c.loops)-1]
+	if err != nil {
+		return nil, err
+	}
+
+	// Exit of loop is after CONTINUE and JUMP.
+	r.IL(block.LBrace.ToContext(), opcode.ILoopIn, body.Length()+2)
+	r.Block(body)
+	r.IL(block.RBrace.ToContext(), opcode.IContinue)
+	r.CleanStack()
+	return r, nil
+}
+
+// compileLoopControl compiles break and continue statement, try blocks in the
+// loop are left before leaving the loop body.
+func (c *Compiler) compileLoopControl(ctx *token.Context, keyword string, code int) (*opcode.CodeBlock, error) {
+	if len(c.loops) == 0 {
+		return nil, NewSemanticError(ctx, "%s is not in a loop", keyword)
+	}
+
+	loop := c.loops[len(c.loops)-1]
+	r, err := c.compileLeaveTryBlocksTo(ctx, loop.tryBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	r.IL(ctx, code)
+	return r, nil
+}
@@ -0,0 +1,117 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/opcode"
+)
+
+func TestCompileLoopStatement(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			`while (true) { 1 }`,
+			code(
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadBool, 1),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IJumpIf, 4),
+				inst(opcode.ILoopIn, 3),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IContinue),
+				inst(opcode.IJump, -8),
+			),
+			data(),
+		},
+		{
+			`for (x in 3) { if (x) { break } else { continue } }`,
+			code(
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 3),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IIter),
+				inst(opcode.INext, 15),
+				inst(opcode.ISStore, 1),
+				inst(opcode.ILoopIn, 13),
+				inst(opcode.IScopeIn),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IJumpIf, 4),
+				inst(opcode.IScopeIn),
+				inst(opcode.IBreak),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IJumpFWD, 3),
+				inst(opcode.IScopeIn),
+				inst(opcode.IContinue),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IContinue),
+				inst(opcode.IJump, -16),
+				inst(opcode.IPop, 1),
+			),
+			data(),
+		},
+		{
+			`while (true) { try { break } catch (e) { 1 } }`,
+			code(
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadBool, 1),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IJumpIf, 14),
+				inst(opcode.ILoopIn, 13),
+				inst(opcode.ITry, 6),
+				inst(opcode.IScopeIn),
+				inst(opcode.IEndTry),
+				inst(opcode.IBreak),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IEndTry),
+				inst(opcode.IJumpFWD, 4),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IContinue),
+				inst(opcode.IJump, -18),
+			),
+			data(),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
+func TestCompileLoopStatementError(t *testing.T) {
+	tests := []testCompilerErrorCase{
+		{
+			`break`,
+			text(
+				"break",
+				"^^^^^",
+				"break is not in a loop",
+				"  at testcase:1:1",
+			),
+		},
+		{
+			`while (true) { let f = fn() { continue }; }`,
+			text(
+				"while (true) { let f = fn() { continue }; }",
+				"                              ^^^^^^^^",
+				"                              continue is not in a loop",
+				"  at testcase:1:31",
+			),
+		},
+		{
+			`for (x in 3) { let x = 1; }`,
+			text(
+				"for (x in 3) { let x = 1; }",
+				"                   ^",
+				"                   variable x redeclared",
+				"  at testcase:1:20",
+				"for (x in 3) { let x = 1; }",
+				"     ^",
+				"     variable x is already declared here",
+				"  at testcase:1:6",
+			),
+		},
+	}
+
+	runCompilerErrorTestCases(t, tests)
+}
@@ -0,0 +1,282 @@
+package compiler
+
+import (
+	"github.com/flily/macaque-lang/ast"
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/opcode"
+	"github.com/flily/macaque-lang/token"
+)
+
+// matchArm is code of an arm being compiled. Tests jump to the next arm when
+// they fail, and operands of the jumps are resolved when the arm is finished.
+type matchArm struct {
+	code  *opcode.CodeBlock
+	fails []int // indexes of JUMPIF to the next arm
+}
+
+func (a *matchArm) jumpIfFalse(ctx *token.Context) {
+	a.fails = append(a.fails, a.code.Length())
+	a.code.IL(ctx, opcode.IJumpIf, 0)
+}
+
+// compileMatchExpression compiles match expression to a sequence of tests. The
+// subject is kept on the stack, each arm tests a copy of it in its own scope,
+// and jumps to the next arm once a test fails. Value of the expression is null
+// if no arm matches. Values of arms are in tail position if the expression is.
+//
+//	match (v) { [x, 1] if g => e, _ => d }
+//	    SCOPEIN
+//	    <v>
+//	    SCOPEIN                     ; arm [x, 1] if g
+//	    SDUP
+//	    SDUP
+//	    ISTYPE   ARRAY
+//	    JUMPIF   L0
+//	    SDUP
+//	    LEN
+//	    LOADINT  2
+//	    BINOP    ==
+//	    JUMPIF   L0
+//	    SDUP
+//	    LOADINT  0
+//	    INDEX
+//	    SSTORE   x
+//	    SDUP
+//	    LOADINT  1
+//	    INDEX
+//	    LOADINT  1
+//	    BINOP    ==
+//	    JUMPIF   L0
+//	    POP      1
+//	    SCOPEIN
+//	    <g>
+//	    SCOPEOUT 1
+//	    JUMPIF   L0
+//	    <e>
+//	    SCOPEOUT 1
+//	    JUMPFWD  L2
+//	L0: CLEAN
+//	    SCOPEOUT 0
+//	    SCOPEIN                     ; arm _
+//	    <d>
+//	    SCOPEOUT 1
+//	    JUMPFWD  L2
+//	L1: CLEAN
+//	    SCOPEOUT 0
+//	    LOADNULL
+//	L2: SCOPEOUT 1
+func (c *Compiler) compileMatchExpression(n *ast.MatchExpression, flag CompilerFlag) (*opcode.CodeBlock, error) {
+	ctx := n.Match.ToContext()
+	r := opcode.NewCodeBlock()
+	r.IL(ctx, opcode.IScopeIn)
+
+	subject, err := c.compileExpression(n.Subject, NewFlag(FlagNone))
+	if err != nil {
+		return nil, err
+	}
+
+	if subject.Determined && subject.Values == 1 {
+		r.Block(subject)
+
+	} else {
+		// The first value is matched, like the value of destructuring let.
+		r.IL(n.Subject.GetContext(), opcode.IScopeIn)
+		r.Block(subject)
+		r.IL(n.Subject.GetContext(), opcode.IStackRev)
+		r.IL(n.Subject.GetContext(), opcode.IScopeOut, 1)
+	}
+
+	catchAll := false
+	arms := make([]*opcode.CodeBlock, len(n.Arms))
+	for i, arm := range n.Arms {
+		if arms[i], err = c.compileMatchArm(arm, flag); err != nil {
+			return nil, err
+		}
+
+		if _, ok := arm.Pattern.(*ast.BindingPattern); ok && arm.Guard == nil {
+			catchAll = true
+		}
+	}
+
+	if !catchAll {
+		c.warn(NewSemanticError(ctx, "match has no catch-all arm"))
+	}
+
+	// Arms are joined from the last one, which jumps over the null fallback.
+	rest := opcode.NewCodeBlock()
+	rest.IL(n.RBrace.ToContext(), opcode.ILoadNull)
+	for i := len(arms) - 1; i >= 0; i-- {
+		armContext := n.Arms[i].GetContext()
+		arm := arms[i]
+		arm.IL(armContext, opcode.IJumpFWD, rest.Length()+2)
+		arm.IL(armContext, opcode.IClean)
+		arm.IL(armContext, opcode.IScopeOut, 0)
+		arm.Block(rest)
+		rest = arm
+	}
+
+	r.Block(rest)
+	r.IL(n.RBrace.ToContext(), opcode.IScopeOut, 1)
+	r.CleanStack().SetValues(1)
+	return r, nil
+}
+
+// compileMatchArm compiles tests, guard and value of an arm in a scope, the
+// scope is left with the value if the arm matches. Jumps of failed tests target
+// the instruction after JUMPFWD appended by caller.
+func (c *Compiler) compileMatchArm(n *ast.MatchArm, flag CompilerFlag) (*opcode.CodeBlock, error) {
+	arm := &matchArm{code: opcode.NewCodeBlock()}
+	ctx := n.Pattern.GetContext()
+
+	c.Context.Variable.EnterScope(FrameScopeBlock)
+	defer c.Context.Variable.LeaveScope()
+
+	arm.code.IL(ctx, opcode.IScopeIn)
+	if p, ok := n.Pattern.(*ast.BindingPattern); !ok || !p.IsWildcard() {
+		arm.code.IL(ctx, opcode.ISDUP)
+		if err := c.compileMatchPattern(n.Pattern, arm); err != nil {
+			return nil, err
+		}
+	}
+
+	if n.Guard != nil {
+		guardContext := n.Guard.GetContext()
+		arm.code.IL(guardContext, opcode.IScopeIn)
+		if err := arm.code.Append(c.compileExpression(n.Guard, NewFlag(FlagNone))); err != nil {
+			return nil, err
+		}
+		arm.code.IL(guardContext, opcode.IScopeOut, 1)
+		arm.jumpIfFalse(guardContext)
+	}
+
+	valueFlag := NewFlag(FlagNone)
+	if flag.Has(FlagTailCall) {
+		valueFlag.Set(FlagTailCall)
+	}
+
+	if err := arm.code.Append(c.compileExpression(n.Value, valueFlag)); err != nil {
+		return nil, err
+	}
+	arm.code.IL(n.Value.GetContext(), opcode.IScopeOut, 1)
+
+	// Failed tests jump to CLEAN after JUMPFWD.
+	next := arm.code.Length() + 1
+	for _, i := range arm.fails {
+		item := &arm.code.Codes[i]
+		item.IL = opcode.NewIL(opcode.IJumpIf, next-i-1)
+	}
+
+	arm.code.CleanStack()
+	return arm.code, nil
+}
+
+// compileMatchPattern tests the value on the top of stack with pattern, the
+// value is popped if the test passes.
+func (c *Compiler) compileMatchPattern(pattern ast.Pattern, arm *matchArm) error {
+	r := arm.code
+	ctx := pattern.GetContext()
+
+	switch p := pattern.(type) {
+	case *ast.LiteralPattern:
+		if err := r.Append(c.compileExpression(p.Value, NewFlag(FlagNone))); err != nil {
+			return err
+		}
+		r.IL(ctx, opcode.IBinOp, int(token.EQ))
+		arm.jumpIfFalse(ctx)
+
+	case *ast.BindingPattern:
+		if p.IsWildcard() {
+			r.IL(ctx, opcode.IPop, 1)
+			break
+		}
+
+		if err := c.compileMatchBinding(p.Name, r); err != nil {
+			return err
+		}
+
+	case *ast.ArrayMatchPattern:
+		length := len(p.Elements)
+		compare := token.EQ
+		if p.Rest != nil {
+			compare = token.GE
+		}
+
+		r.IL(ctx, opcode.ISDUP)
+		r.IL(ctx, opcode.IIsType, int(object.ObjectTypeArray))
+		arm.jumpIfFalse(ctx)
+		r.IL(ctx, opcode.ISDUP)
+		r.IL(ctx, opcode.ILen)
+		r.IL(ctx, opcode.ILoadInt, length)
+		r.IL(ctx, opcode.IBinOp, int(compare))
+		arm.jumpIfFalse(ctx)
+
+		for i, elem := range p.Elements {
+			if b, ok := elem.(*ast.BindingPattern); ok && b.IsWildcard() {
+				continue
+			}
+
+			elemContext := elem.GetContext()
+			r.IL(elemContext, opcode.ISDUP)
+			r.IL(elemContext, opcode.ILoadInt, i)
+			r.IL(elemContext, opcode.IIndex)
+			if err := c.compileMatchPattern(elem, arm); err != nil {
+				return err
+			}
+		}
+
+		if p.Rest != nil {
+			restContext := p.Rest.GetContext()
+			r.IL(restContext, opcode.ISDUP)
+			r.IL(restContext, opcode.ILoadInt, length)
+			r.IL(restContext, opcode.ILoadNull)
+			r.IL(restContext, opcode.ISlice)
+			if err := c.compileMatchBinding(p.Rest, r); err != nil {
+				return err
+			}
+		}
+
+		r.IL(ctx, opcode.IPop, 1)
+
+	case *ast.HashMatchPattern:
+		r.IL(ctx, opcode.ISDUP)
+		r.IL(ctx, opcode.IIsType, int(object.ObjectTypeHash))
+		arm.jumpIfFalse(ctx)
+
+		for _, pair := range p.Pairs {
+			keyContext := pair.Key.GetContext()
+			r.IL(keyContext, opcode.ISDUP)
+			if name, ok := pair.Key.(*ast.Identifier); ok {
+				key := c.Context.Literal.ReferenceString(name.Value)
+				r.IL(keyContext, opcode.ILoad, int(key))
+
+			} else if err := r.Append(c.compileExpression(pair.Key, NewFlag(FlagNone))); err != nil {
+				return err
+			}
+
+			r.IL(keyContext, opcode.IIndex)
+			if err := c.compileMatchPattern(pair.Value, arm); err != nil {
+				return err
+			}
+		}
+
+		r.IL(ctx, opcode.IPop, 1)
+	}
+
+	return nil
+}
+
+// compileMatchBinding stores the value on the top of stack to a new variable in
+// scope of the arm.
+func (c *Compiler) compileMatchBinding(name *ast.Identifier, r *opcode.CodeBlock) error {
+	if err := c.checkLocalName(name.Value, name.GetContext()); err != nil {
+		return err
+	}
+
+	offset, ok := c.Context.Variable.DefineVariable(name.Value, name.GetContext())
+	if !ok {
+		return c.makeRedeclaredError(name.Value, name.GetContext())
+	}
+
+	r.IL(name.GetContext(), opcode.ISStore, offset)
+	return nil
+}
@@ -0,0 +1,183 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/opcode"
+	"github.com/flily/macaque-lang/token"
+)
+
+func TestCompileMatchExpression(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			`match (1) { [a, _] if a => a, _ => 0 };`,
+			code(
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IScopeIn),
+				inst(opcode.ISDUP),
+				inst(opcode.ISDUP),
+				inst(opcode.IIsType, int(object.ObjectTypeArray)),
+				inst(opcode.IJumpIf, 17),
+				inst(opcode.ISDUP),
+				inst(opcode.ILen),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IBinOp, int(token.EQ)),
+				inst(opcode.IJumpIf, 12),
+				inst(opcode.ISDUP),
+				inst(opcode.ILoadInt, 0),
+				inst(opcode.IIndex),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IPop, 1),
+				inst(opcode.IScopeIn),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IJumpIf, 3),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IJumpFWD, 9),
+				inst(opcode.IClean),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IScopeIn),
+				inst(opcode.ILoadInt, 0),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IJumpFWD, 3),
+				inst(opcode.IClean),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.ILoadNull),
+				inst(opcode.IScopeOut, 1),
+			),
+			data(),
+		},
+		{
+			`match ({}) { {k: -1, "v": 2.5} => 1, x => x };`,
+			code(
+				inst(opcode.IScopeIn),
+				inst(opcode.IMakeHash, 0),
+				inst(opcode.IScopeIn),
+				inst(opcode.ISDUP),
+				inst(opcode.ISDUP),
+				inst(opcode.IIsType, int(object.ObjectTypeHash)),
+				inst(opcode.IJumpIf, 17),
+				inst(opcode.ISDUP),
+				inst(opcode.ILoad, 0),
+				inst(opcode.IIndex),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IUniOp, int(token.Minus)),
+				inst(opcode.IBinOp, int(token.EQ)),
+				inst(opcode.IJumpIf, 10),
+				inst(opcode.ISDUP),
+				inst(opcode.ILoad, 1),
+				inst(opcode.IIndex),
+				inst(opcode.ILoad, 2),
+				inst(opcode.IBinOp, int(token.EQ)),
+				inst(opcode.IJumpIf, 4),
+				inst(opcode.IPop, 1),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IJumpFWD, 11),
+				inst(opcode.IClean),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.IScopeIn),
+				inst(opcode.ISDUP),
+				inst(opcode.ISStore, 1),
+				inst(opcode.ISLoad, 1),
+				inst(opcode.IScopeOut, 1),
+				inst(opcode.IJumpFWD, 3),
+				inst(opcode.IClean),
+				inst(opcode.IScopeOut, 0),
+				inst(opcode.ILoadNull),
+				inst(opcode.IScopeOut, 1),
+			),
+			data(
+				object.NewString("k"),
+				object.NewString("v"),
+				object.NewFloat(2.5),
+			),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
+func TestCompileMatchExpressionError(t *testing.T) {
+	tests := []testCompilerErrorCase{
+		{
+			`match (1) { [a, a] => a, _ => 0 };`,
+			text(
+				"match (1) { [a, a] => a, _ => 0 };",
+				"                ^",
+				"                variable a redeclared",
+				"  at testcase:1:17",
+				"match (1) { [a, a] => a, _ => 0 };",
+				"             ^",
+				"             variable a is already declared here",
+				"  at testcase:1:14",
+			),
+		},
+		{
+			`match (1) { {k: v} => v, _ => v };`,
+			text(
+				"match (1) { {k: v} => v, _ => v };",
+				"                              ^",
+				"                              variable v undefined",
+				"  at testcase:1:31",
+			),
+		},
+	}
+
+	runCompilerErrorTestCases(t, tests)
+}
+
+func TestCompileMatchWarning(t *testing.T) {
+	tests := []struct {
+		code     string
+		warnings []string
+	}{
+		{
+			`match (1) { 1 => 2, x if x > 1 => x };`,
+			[]string{
+				text(
+					"match (1) { 1 => 2, x if x > 1 => x };",
+					"^^^^^",
+					"match has no catch-all arm",
+					"  at testcase:1:1",
+				),
+			},
+		},
+		{
+			`match (1) { 1 => 2, x => fn() { match (x) {} } };`,
+			[]string{
+				text(
+					"match (1) { 1 => 2, x => fn() { match (x) {} } };",
+					"                                ^^^^^",
+					"                                match has no catch-all arm",
+					"  at testcase:1:33",
+				),
+			},
+		},
+		{
+			`match (1) { [] => 0, _ => 2 };`,
+			nil,
+		},
+	}
+
+	for _, c := range tests {
+		compiler, _, err := testCompileCode(t, c.code)
+		if err != nil {
+			t.Fatalf("compiler error:\n%s", err)
+		}
+
+		if len(compiler.Warnings) != len(c.warnings) {
+			t.Fatalf("wrong number of warnings of %s, expected %d, got %d",
+				c.code, len(c.warnings), len(compiler.Warnings))
+		}
+
+		for i, w := range compiler.Warnings {
+			if got := w.Error(); got != c.warnings[i] {
+				t.Errorf("wrong warning:\n%s\nexpected:\n%s", got, c.warnings[i])
+			}
+		}
+	}
+}
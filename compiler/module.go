@@ -0,0 +1,298 @@
+package compiler
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/flily/macaque-lang/ast"
+	"github.com/flily/macaque-lang/lex"
+	"github.com/flily/macaque-lang/opcode"
+	"github.com/flily/macaque-lang/std"
+	"github.com/flily/macaque-lang/token"
+)
+
+const (
+	ModuleFileExtension = ".mq"
+	ModulePathEnv       = "MACAQUE_PATH"
+)
+
+// ModuleContext holds all modules imported in a compilation. It is shared by
+// compilers of all modules, so a module file is compiled only once.
+type ModuleContext struct {
+	Modules    []*opcode.Module
+	SearchPath []string
+	files      map[string]*opcode.Module
+}
+
+func NewModuleContext() *ModuleContext {
+	c := &ModuleContext{
+		files: make(map[string]*opcode.Module),
+	}
+
+	return c
+}
+
+func (c *ModuleContext) AddSearchPath(dirs ...string) {
+	for _, dir := range dirs {
+		if len(dir) > 0 {
+			c.SearchPath = append(c.SearchPath, dir)
+		}
+	}
+}
+
+// Lookup finds a compiled module by its canonical name.
+func (c *ModuleContext) Lookup(canonical string) (*opcode.Module, bool) {
+	m, ok := c.files[canonical]
+	return m, ok
+}
+
+func (c *ModuleContext) Add(m *opcode.Module) int {
+	n := len(c.Modules)
+	m.Index = n
+	for _, f := range m.Functions {
+		if f != nil {
+			f.Module = n + 1
+		}
+	}
+
+	c.Modules = append(c.Modules, m)
+	c.files[m.Canonical] = m
+	return n
+}
+
+// Resolve finds the file of module path imported in file from. Path starts with
+// "./" or "../" is relative to the directory of from only, otherwise directory
+// of from, directories in SearchPath and in environment variable MACAQUE_PATH
+// are searched in order. Extension ".mq" is added if path does not have one.
+func (c *ModuleContext) Resolve(from string, modulePath string) (string, bool) {
+	name := filepath.FromSlash(modulePath)
+	if filepath.Ext(name) == "" {
+		name += ModuleFileExtension
+	}
+
+	var candidates []string
+	switch {
+	case filepath.IsAbs(name):
+		candidates = []string{name}
+
+	case strings.HasPrefix(modulePath, "./") || strings.HasPrefix(modulePath, "../"):
+		candidates = []string{filepath.Join(filepath.Dir(from), name)}
+
+	default:
+		candidates = []string{filepath.Join(filepath.Dir(from), name)}
+		for _, dir := range c.searchPath() {
+			candidates = append(candidates, filepath.Join(dir, name))
+		}
+	}
+
+	for _, filename := range candidates {
+		if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+			return filepath.Clean(filename), true
+		}
+	}
+
+	return "", false
+}
+
+func (c *ModuleContext) searchPath() []string {
+	dirs := make([]string, 0, len(c.SearchPath))
+	dirs = append(dirs, c.SearchPath...)
+	for _, dir := range filepath.SplitList(os.Getenv(ModulePathEnv)) {
+		if len(dir) > 0 {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// CanonicalName returns the unique name of a module file, which is the absolute
+// path with symbolic links evaluated.
+func CanonicalName(filename string) string {
+	name, err := filepath.Abs(filename)
+	if err != nil {
+		return filepath.Clean(filename)
+	}
+
+	if real, err := filepath.EvalSymlinks(name); err == nil {
+		name = real
+	}
+
+	return name
+}
+
+// newModuleCompiler creates a compiler for an imported module. The module has
+// its own variables and functions, but shares literals and modules with parent.
+func newModuleCompiler(parent *Compiler, canonical string, ctx *token.Context) *Compiler {
+	c := &Compiler{
+		Context:       newModuleCompilerContext(parent.Context),
+		parent:        parent,
+		canonical:     canonical,
+		importContext: ctx,
+	}
+
+	c.Context.Variable.EnterScope(FrameScopeFunction)
+	return c
+}
+
+func (c *Compiler) AddSearchPath(dirs ...string) {
+	c.Context.Modules.AddSearchPath(dirs...)
+}
+
+func (c *Compiler) canonicalName() string {
+	if len(c.canonical) == 0 {
+		c.canonical = CanonicalName(c.Filename)
+	}
+
+	return c.canonical
+}
+
+// isImporting checks whether module canonical is being compiled in the chain of
+// importing, which means an import cycle.
+func (c *Compiler) isImporting(canonical string) bool {
+	for p := c; p != nil; p = p.parent {
+		if p.canonicalName() == canonical {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Compiler) makeImportCycleError(n *ast.ImportStatement, canonical string) *SematicError {
+	err := NewSemanticError(n.Target.ToContext(),
+		"import cycle not allowed, module %s is being imported", n.Target.Content)
+
+	for p := c; p.parent != nil && p.canonicalName() != canonical; p = p.parent {
+		err = err.WithInfo(p.importContext, "imported here")
+	}
+
+	return err
+}
+
+func (c *Compiler) importModule(n *ast.ImportStatement) (*opcode.Module, error) {
+	if m, ok := std.Lookup(n.Path); ok {
+		return c.importSystemModule(n, m), nil
+	}
+
+	filename, found := c.Context.Modules.Resolve(c.Filename, n.Path)
+	if !found {
+		return nil, NewSemanticError(n.Target.ToContext(),
+			"module %s not found", n.Target.Content)
+	}
+
+	canonical := CanonicalName(filename)
+	if m, ok := c.Context.Modules.Lookup(canonical); ok {
+		return m, nil
+	}
+
+	if c.isImporting(canonical) {
+		return nil, c.makeImportCycleError(n, canonical)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, NewSemanticError(n.Target.ToContext(),
+			"can not import module %s: %s", n.Target.Content, err)
+	}
+
+	mc := newModuleCompiler(c, canonical, n.Target.ToContext())
+	main, err := mc.CompileCode(filename, content)
+	if err != nil {
+		return nil, err
+	}
+
+	module := mc.Context.LinkModule(n.Path, canonical, main)
+	c.Context.Modules.Add(module)
+	return module, nil
+}
+
+// importSystemModule makes a module of standard library. Main function of the
+// module returns a hash of its native functions, which are bound by their
+// native names, so VM finds them in the standard library.
+func (c *Compiler) importSystemModule(n *ast.ImportStatement, m *std.Module) *opcode.Module {
+	if module, ok := c.Context.Modules.Lookup(m.Name); ok {
+		return module
+	}
+
+	ctx := n.Target.ToContext()
+	mc := newModuleCompiler(c, m.Name, ctx)
+	main := opcode.NewCodeBlock()
+	names := m.Names()
+	for _, name := range names {
+		main.IL(ctx, opcode.ILoad, int(mc.Context.Literal.ReferenceString(name)))
+		main.IL(ctx, opcode.INative, mc.Context.Natives.Add(m.NativeName(name)))
+	}
+
+	main.IL(ctx, opcode.IMakeHash, len(names))
+	main.IL(ctx, opcode.IReturn)
+
+	module := mc.Context.LinkModule(n.Path, m.Name, main)
+	module.Type = opcode.ModuleTypeSystem
+	c.Context.Modules.Add(module)
+	return module
+}
+
+// importName returns the name of variable which module bound to. It is the
+// alias name if given, or the base name of module path without extension.
+func importName(n *ast.ImportStatement) (string, *token.Context, error) {
+	if n.Name != nil {
+		return n.Name.Value, n.Name.GetContext(), nil
+	}
+
+	ctx := n.Target.ToContext()
+	base := path.Base(n.Path)
+	name := base[:len(base)-len(path.Ext(base))]
+	if !isIdentifier(name) {
+		err := NewSemanticError(ctx,
+			"module name %s is not a valid identifier, an alias name is required", name)
+		return "", nil, err
+	}
+
+	return name, ctx, nil
+}
+
+func isIdentifier(s string) bool {
+	if len(s) <= 0 || lex.IsDigit(s[0]) {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !lex.IsUpper(c) && !lex.IsLower(c) && !lex.IsDigit(c) && c != '_' {
+			return false
+		}
+	}
+
+	return token.CheckKeywordToken(s) == token.Identifier
+}
+
+func (c *Compiler) compileImportStatement(n *ast.ImportStatement) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+	ctx := n.GetContext()
+
+	name, nameContext, err := importName(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkLocalName(name, nameContext); err != nil {
+		return nil, err
+	}
+
+	module, err := c.importModule(n)
+	if err != nil {
+		return nil, err
+	}
+
+	index, ok := c.Context.Variable.DefineVariable(name, nameContext)
+	if !ok {
+		return nil, c.makeRedeclaredError(name, nameContext)
+	}
+
+	r.IL(ctx, opcode.IImport, module.Index)
+	r.IL(ctx, opcode.ISStore, index)
+	return r, nil
+}
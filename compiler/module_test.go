@@ -0,0 +1,247 @@
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flily/macaque-lang/opcode"
+)
+
+func writeModuleFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("create directory failed: %s", err)
+		}
+
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatalf("write file %s failed: %s", name, err)
+		}
+	}
+}
+
+func compileModuleFile(c *Compiler, dir string, name string) error {
+	filename := filepath.Join(dir, filepath.FromSlash(name))
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.CompileCode(filename, content)
+	return err
+}
+
+func TestCompileImportStatementError(t *testing.T) {
+	tests := []testCompilerErrorCase{
+		{
+			text(
+				`import "lib/foo-bar";`,
+			),
+			text(
+				`import "lib/foo-bar";`,
+				`       ^^^^^^^^^^^^^`,
+				`       module name foo-bar is not a valid identifier, an alias name is required`,
+				`  at testcase:1:8`,
+			),
+		},
+		{
+			text(
+				`import "lib/not_exists";`,
+			),
+			text(
+				`import "lib/not_exists";`,
+				`       ^^^^^^^^^^^^^^^^`,
+				`       module "lib/not_exists" not found`,
+				`  at testcase:1:8`,
+			),
+		},
+	}
+
+	runCompilerErrorTestCases(t, tests)
+}
+
+func TestModuleResolve(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"main.mq":         ``,
+		"local.mq":        ``,
+		"lib/local.mq":    ``,
+		"lib/util.mq":     ``,
+		"sys/util.mq":     ``,
+		"sys/sys_only.mq": ``,
+		"env/env_only.mq": ``,
+		"env/util.mq":     ``,
+	})
+
+	t.Setenv(ModulePathEnv, filepath.Join(dir, "env"))
+
+	ctx := NewModuleContext()
+	ctx.AddSearchPath(filepath.Join(dir, "sys"), "")
+
+	from := filepath.Join(dir, "main.mq")
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"local", "local.mq"},
+		{"./local.mq", "local.mq"},
+		{"lib/util", "lib/util.mq"},
+		{"util", "sys/util.mq"},
+		{"sys_only", "sys/sys_only.mq"},
+		{"env_only", "env/env_only.mq"},
+		{filepath.ToSlash(filepath.Join(dir, "lib", "local")), "lib/local.mq"},
+		{"./util", ""},
+		{"../env_only", ""},
+		{"lib", ""},
+		{"not_exists", ""},
+	}
+
+	for _, c := range tests {
+		got, found := ctx.Resolve(from, c.path)
+		if len(c.expected) == 0 {
+			if found {
+				t.Errorf("module %s should not be found, got %s", c.path, got)
+			}
+
+			continue
+		}
+
+		expected := filepath.Join(dir, filepath.FromSlash(c.expected))
+		if !found || got != expected {
+			t.Errorf("resolve module %s wrong, expect %s, got %s (%v)",
+				c.path, expected, got, found)
+		}
+	}
+}
+
+func TestImportModuleOnlyOnce(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"main.mq": text(
+			`import "lib/a";`,
+			`import b "./lib/b";`,
+			`import a2 "lib/../lib/a.mq";`,
+		),
+		"lib/a.mq": `import "b"; b`,
+		"lib/b.mq": `42`,
+	})
+
+	c := NewCompiler()
+	if err := compileModuleFile(c, dir, "main.mq"); err != nil {
+		t.Fatalf("compiler error:\n%s", err)
+	}
+
+	modules := c.Context.Modules.Modules
+	if len(modules) != 2 {
+		t.Fatalf("wrong number of modules, expect 2, got %d", len(modules))
+	}
+
+	expecteds := []string{"lib/b.mq", "lib/a.mq"}
+	for i, m := range modules {
+		expected := CanonicalName(filepath.Join(dir, filepath.FromSlash(expecteds[i])))
+		if m.Canonical != expected {
+			t.Errorf("wrong canonical name of module %d, expect %s, got %s",
+				i, expected, m.Canonical)
+		}
+	}
+}
+
+func TestImportCycleError(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"main.mq": `import "a";`,
+		"a.mq":    `import "b";`,
+		"b.mq":    `import "c";`,
+		"c.mq":    `import "./a.mq";`,
+		"self.mq": `import "self";`,
+	})
+
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{
+			"main.mq",
+			text(
+				`import "./a.mq";`,
+				`       ^^^^^^^^`,
+				`       import cycle not allowed, module "./a.mq" is being imported`,
+				`  at `+path("c.mq")+`:1:8`,
+				`import "c";`,
+				`       ^^^`,
+				`       imported here`,
+				`  at `+path("b.mq")+`:1:8`,
+				`import "b";`,
+				`       ^^^`,
+				`       imported here`,
+				`  at `+path("a.mq")+`:1:8`,
+			),
+		},
+		{
+			"self.mq",
+			text(
+				`import "self";`,
+				`       ^^^^^^`,
+				`       import cycle not allowed, module "self" is being imported`,
+				`  at `+path("self.mq")+`:1:8`,
+			),
+		},
+	}
+
+	for _, c := range tests {
+		err := compileModuleFile(NewCompiler(), dir, c.file)
+		if err == nil {
+			t.Fatalf("compilation of %s should fail", c.file)
+		}
+
+		if got := err.Error(); got != c.expected {
+			t.Errorf("incorrect error message:\n%s\nexpect:\n%s", got, c.expected)
+		}
+	}
+}
+
+func TestImportSystemModule(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"main.mq": text(
+			`import "std/math";`,
+			`import "lib/a";`,
+		),
+		"lib/a.mq": `import m "std/math"; m`,
+	})
+
+	c := NewCompiler()
+	if err := compileModuleFile(c, dir, "main.mq"); err != nil {
+		t.Fatalf("compiler error:\n%s", err)
+	}
+
+	modules := c.Context.Modules.Modules
+	if len(modules) != 2 {
+		t.Fatalf("wrong number of modules, expect 2, got %d", len(modules))
+	}
+
+	m := modules[0]
+	if m.Canonical != "std/math" || m.Type != opcode.ModuleTypeSystem {
+		t.Errorf("wrong system module: %s %d", m.Canonical, m.Type)
+	}
+
+	main := m.Main().Link()
+	last := main[len(main)-2]
+	if last.Name != opcode.IMakeHash || last.Operand0 != len(c.Context.Natives.Names) {
+		t.Errorf("wrong main function of system module: %v", main)
+	}
+
+	for _, name := range c.Context.Natives.Names {
+		if !strings.HasPrefix(name, "std/math.") {
+			t.Errorf("wrong native name %s", name)
+		}
+	}
+}
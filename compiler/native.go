@@ -0,0 +1,43 @@
+package compiler
+
+// NativeContext holds names of native functions provided by host code. Names
+// are bound before compiling, and functions are registered to VM with the same
+// names, which are resolved when the code runs.
+type NativeContext struct {
+	Names []string
+	index map[string]int
+}
+
+func NewNativeContext() *NativeContext {
+	c := &NativeContext{
+		index: make(map[string]int),
+	}
+
+	return c
+}
+
+// Add binds name to a native function, and returns its index. Index of name
+// already bound is returned directly.
+func (c *NativeContext) Add(name string) int {
+	if i, ok := c.index[name]; ok {
+		return i
+	}
+
+	i := len(c.Names)
+	c.Names = append(c.Names, name)
+	c.index[name] = i
+	return i
+}
+
+func (c *NativeContext) Lookup(name string) (int, bool) {
+	i, ok := c.index[name]
+	return i, ok
+}
+
+// RegisterNative binds names to native functions, which can be referenced like
+// variables. Native functions are shadowed by variables with the same name.
+func (c *Compiler) RegisterNative(names ...string) {
+	for _, name := range names {
+		c.Context.Natives.Add(name)
+	}
+}
@@ -0,0 +1,63 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/opcode"
+)
+
+func TestCompileNativeFunctions(t *testing.T) {
+	tests := []struct {
+		code    string
+		natives []string
+		codes   []opcode.Opcode
+	}{
+		{
+			`add(1, 2)`,
+			[]string{"print", "add"},
+			code(
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.INative, 1),
+				inst(opcode.ICall, 2),
+			),
+		},
+		{
+			// Native functions shadow builtin functions.
+			`typeof(1)`,
+			[]string{"typeof"},
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.INative, 0),
+				inst(opcode.ICall, 1),
+			),
+		},
+		{
+			// Native functions are shadowed by variables.
+			`let add = 1; add`,
+			[]string{"add"},
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.ISLoad, 1),
+			),
+		},
+	}
+
+	for _, c := range tests {
+		compiler := NewCompiler()
+		compiler.RegisterNative(c.natives...)
+		program := parseCode(t, c.code)
+		block, err := compiler.CompileASTSnippet(program)
+		if err != nil {
+			t.Fatalf("compiler error:\n%s", err)
+		}
+
+		page := compiler.Link(block)
+		checkInstructions(t, c.code, page, compiler, c.codes)
+		if len(page.Natives) != len(c.natives) {
+			t.Errorf("wrong natives of code page: %v", page.Natives)
+		}
+	}
+}
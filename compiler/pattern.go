@@ -0,0 +1,106 @@
+package compiler
+
+import (
+	"github.com/flily/macaque-lang/ast"
+	"github.com/flily/macaque-lang/opcode"
+)
+
+// compileLetPattern compiles destructuring let statement. The value is kept on
+// the stack while its elements are stored to variables, missing elements are
+// null.
+//
+//	let [a, ...r] = v           let {k: a} = v
+//	    <v>                         <v>
+//	    SDUP                        SDUP
+//	    LOADINT  0                  LOAD     "k"
+//	    INDEX                       INDEX
+//	    SSTORE   a                  SSTORE   a
+//	    SDUP                        POP      1
+//	    LOADINT  1
+//	    LOADNULL
+//	    SLICE
+//	    SSTORE   r
+//	    POP      1
+func (c *Compiler) compileLetPattern(n *ast.LetStatement) (*opcode.CodeBlock, error) {
+	r := opcode.NewCodeBlock()
+	ctx := n.Pattern.GetContext()
+
+	if count := n.Expressions.Length(); count != 1 {
+		return nil, NewSemanticError(n.Expressions.GetContext(),
+			"destructuring requires exactly one value, but got %d", count)
+	}
+
+	define := c.Context.Variable.DefineVariable
+	if n.IsVar() {
+		define = c.Context.Variable.DefineMutableVariable
+	}
+
+	// Element of the value is pushed by index code, then stored to variable.
+	type binding struct {
+		name  *ast.Identifier
+		index *opcode.CodeBlock
+	}
+
+	var bindings []binding
+	switch p := n.Pattern.(type) {
+	case *ast.ArrayPattern:
+		for i, item := range p.Elements.Identifiers {
+			index := opcode.NewCodeBlock()
+			index.IL(item.Identifier.GetContext(), opcode.ILoadInt, i)
+			index.IL(item.Identifier.GetContext(), opcode.IIndex)
+			bindings = append(bindings, binding{item.Identifier, index})
+		}
+
+		if p.Rest != nil {
+			index := opcode.NewCodeBlock()
+			index.IL(p.Rest.GetContext(), opcode.ILoadInt, p.Elements.Length())
+			index.IL(p.Rest.GetContext(), opcode.ILoadNull)
+			index.IL(p.Rest.GetContext(), opcode.ISlice)
+			bindings = append(bindings, binding{p.Rest, index})
+		}
+
+	case *ast.HashPattern:
+		for _, pair := range p.Pairs {
+			key := c.Context.Literal.ReferenceString(pair.Key.Value)
+			index := opcode.NewCodeBlock()
+			index.IL(pair.Key.GetContext(), opcode.ILoad, int(key))
+			index.IL(pair.Key.GetContext(), opcode.IIndex)
+			bindings = append(bindings, binding{pair.Value, index})
+		}
+	}
+
+	offsets := make([]int, len(bindings))
+	for i, b := range bindings {
+		offset, ok := define(b.name.Value, b.name.GetContext())
+		if !ok {
+			return nil, c.makeRedeclaredError(b.name.Value, b.name.GetContext())
+		}
+		offsets[i] = offset
+	}
+
+	value, err := c.compileExpression(n.Expressions, NewFlag(FlagNone))
+	if err != nil {
+		return nil, err
+	}
+
+	if value.Determined && value.Values == 1 {
+		r.Block(value)
+
+	} else {
+		// The first value is destructed, like the first variable of let.
+		r.IL(ctx, opcode.IScopeIn)
+		r.Block(value)
+		r.IL(ctx, opcode.IStackRev)
+		r.IL(ctx, opcode.IScopeOut, 1)
+	}
+
+	for i, b := range bindings {
+		r.IL(b.name.GetContext(), opcode.ISDUP)
+		r.Block(b.index)
+		c.compileVariableStore(n, b.name.Value, offsets[i], r)
+	}
+
+	r.IL(ctx, opcode.IPop, 1)
+	r.CleanStack()
+	return r, nil
+}
@@ -0,0 +1,84 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/opcode"
+)
+
+func TestCompileLetPattern(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			`let [a, ...r] = [1, 2];`,
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.IMakeList, 2),
+				inst(opcode.ISDUP),
+				inst(opcode.ILoadInt, 0),
+				inst(opcode.IIndex),
+				inst(opcode.ISStore, 1),
+				inst(opcode.ISDUP),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadNull),
+				inst(opcode.ISlice),
+				inst(opcode.ISStore, 2),
+				inst(opcode.IPop, 1),
+			),
+			data(),
+		},
+		{
+			`var {k, v: x} = {};`,
+			code(
+				inst(opcode.IMakeHash, 0),
+				inst(opcode.ISDUP),
+				inst(opcode.ILoad, 0),
+				inst(opcode.IIndex),
+				inst(opcode.IMakeCell),
+				inst(opcode.ISStore, 1),
+				inst(opcode.ISDUP),
+				inst(opcode.ILoad, 1),
+				inst(opcode.IIndex),
+				inst(opcode.IMakeCell),
+				inst(opcode.ISStore, 2),
+				inst(opcode.IPop, 1),
+			),
+			data(
+				object.NewString("k"),
+				object.NewString("v"),
+			),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
+func TestCompileLetPatternError(t *testing.T) {
+	tests := []testCompilerErrorCase{
+		{
+			`let [a, b] = 1, 2;`,
+			text(
+				"let [a, b] = 1, 2;",
+				"             ^^ ^",
+				"             destructuring requires exactly one value, but got 2",
+				"  at testcase:1:14",
+			),
+		},
+		{
+			`let {a, b: a} = {};`,
+			text(
+				"let {a, b: a} = {};",
+				"           ^",
+				"           variable a redeclared",
+				"  at testcase:1:12",
+				"let {a, b: a} = {};",
+				"     ^",
+				"     variable a is already declared here",
+				"  at testcase:1:6",
+			),
+		},
+	}
+
+	runCompilerErrorTestCases(t, tests)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/flily/macaque-lang/opcode"
+	"github.com/flily/macaque-lang/token"
 )
 
 func TestCompileLetStatement(t *testing.T) {
@@ -50,7 +51,7 @@ func TestCompileLetStatement(t *testing.T) {
 				inst(opcode.IClean),
 				inst(opcode.ISLoad, 1),
 				inst(opcode.ISLoad, 4),
-				inst(opcode.IBinOp, 23),
+				inst(opcode.IBinOp, int(token.Plus)),
 			),
 			data(),
 		},
@@ -71,7 +72,7 @@ func TestCompileLetStatement(t *testing.T) {
 				inst(opcode.IClean),
 				inst(opcode.ISLoad, 1),
 				inst(opcode.ISLoad, 4),
-				inst(opcode.IBinOp, 23),
+				inst(opcode.IBinOp, int(token.Plus)),
 			),
 			data(),
 		},
@@ -149,7 +150,7 @@ func TestCompileLetStatement(t *testing.T) {
 				inst(opcode.IScopeIn),
 				inst(opcode.ISLoad, 1),
 				inst(opcode.ILoadInt, 5),
-				inst(opcode.IBinOp, 30),
+				inst(opcode.IBinOp, int(token.GT)),
 				inst(opcode.IScopeOut, 1),
 				inst(opcode.IJumpIf, 4),
 				inst(opcode.IScopeIn),
@@ -194,6 +195,106 @@ func TestCompileLetStatementRedefined(t *testing.T) {
 	runCompilerErrorTestCases(t, tests)
 }
 
+func TestCompileScopedVariable(t *testing.T) {
+	tests := []testCompilerCase{
+		{
+			`let a, @b, $c = 1, 2, 3; @b + $c`,
+			code(
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.ILoadInt, 2),
+				inst(opcode.ILoadInt, 3),
+				inst(opcode.IGStore, 0),
+				inst(opcode.IMStore, 0),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IClean),
+				inst(opcode.IMLoad, 0),
+				inst(opcode.IGLoad, 0),
+				inst(opcode.IBinOp, int(token.Plus)),
+			),
+			data(),
+		},
+		{
+			text(
+				"var @n = 0;",
+				"let f = fn() { @n + 1 };",
+			),
+			code(
+				inst(opcode.ILoadInt, 0),
+				inst(opcode.IMStore, 0),
+				inst(opcode.IClean),
+				inst(opcode.IMakeFunc, 1, 0),
+				inst(opcode.ISStore, 1),
+				inst(opcode.IHalt),
+				inst(opcode.IScopeIn),
+				inst(opcode.IMLoad, 0),
+				inst(opcode.ILoadInt, 1),
+				inst(opcode.IBinOp, int(token.Plus)),
+				inst(opcode.IReturn),
+				inst(opcode.IHalt),
+			),
+			data(),
+		},
+	}
+
+	runCompilerTestCases(t, tests)
+}
+
+func TestCompileScopedVariableError(t *testing.T) {
+	tests := []testCompilerErrorCase{
+		{
+			`let f = fn(a, @b) { a }; 1`,
+			text(
+				"let f = fn(a, @b) { a }; 1",
+				"              ^^",
+				"              module variable @b can not be defined here",
+				"  at testcase:1:15",
+			),
+		},
+		{
+			`for ($x in [1]) { $x }`,
+			text(
+				"for ($x in [1]) { $x }",
+				"     ^^",
+				"     global variable $x can not be defined here",
+				"  at testcase:1:6",
+			),
+		},
+		{
+			`match (1) { @x => 2 };`,
+			text(
+				"match (1) { @x => 2 };",
+				"            ^^",
+				"            module variable @x can not be defined here",
+				"  at testcase:1:13",
+			),
+		},
+		{
+			`let @a = 1; let f = fn() { let @a = 2; };`,
+			text(
+				"let @a = 1; let f = fn() { let @a = 2; };",
+				"                               ^^",
+				"                               variable @a redeclared",
+				"  at testcase:1:32",
+				"let @a = 1; let f = fn() { let @a = 2; };",
+				"    ^^",
+				"    variable @a is already declared here",
+				"  at testcase:1:5",
+			),
+		},
+		{
+			`let f = fn() { @a }; f()`,
+			text(
+				"let f = fn() { @a }; f()",
+				"               ^^",
+				"               variable @a undefined",
+				"  at testcase:1:16",
+			),
+		},
+	}
+
+	runCompilerErrorTestCases(t, tests)
+}
+
 func TestCompileFunctionsWithoutReturnValue(t *testing.T) {
 	tests := []testCompilerCase{
 		{
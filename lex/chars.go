@@ -92,3 +92,15 @@ func IsUpper(c byte) bool {
 func IsPunct(c byte) bool {
 	return byteFlagMap[c]&flagPunctuation != 0
 }
+
+// IsIdentifierPrefix checks whether c is a prefix of identifier, '@' for module
+// variables and '$' for global variables.
+func IsIdentifierPrefix(c byte) bool {
+	return c == '@' || c == '$'
+}
+
+// IsIdentifierSuffix checks whether c is a suffix of identifier, '?' for
+// predicate functions and '!' for mutating functions.
+func IsIdentifierSuffix(c byte) bool {
+	return c == '?' || c == '!'
+}
@@ -109,3 +109,16 @@ func TestIsPunct(t *testing.T) {
 		}
 	}
 }
+
+func TestIsIdentifierPrefixAndSuffix(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		c := byte(i)
+		if in, is := inBytesSet(c, []byte{'@', '$'}), IsIdentifierPrefix(c); in != is {
+			t.Errorf("IsIdentifierPrefix('%c' %d) = %v, want %v", i, i, is, in)
+		}
+
+		if in, is := inBytesSet(c, []byte{'?', '!'}), IsIdentifierSuffix(c); in != is {
+			t.Errorf("IsIdentifierSuffix('%c' %d) = %v, want %v", i, i, is, in)
+		}
+	}
+}
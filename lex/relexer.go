@@ -19,6 +19,22 @@ func NewRecursiveScanner(filename string) *RecursiveScanner {
 	return s
 }
 
+// NewEmbeddedScanner creates a scanner of code embedded in a token, like
+// expressions in string interpolation. Tokens are positioned in the source file
+// of info, and the code starts at line and column.
+func NewEmbeddedScanner(info *token.FileInfo, code []byte, line int, column int) *RecursiveScanner {
+	s := &RecursiveScanner{
+		FileReader: token.FileReader{
+			FileInfo: info,
+			Source:   code,
+			Line:     line,
+			Column:   column,
+		},
+	}
+
+	return s
+}
+
 func (s *RecursiveScanner) Scan() (*token.TokenContext, error) {
 	return s.scanStateInit()
 }
@@ -63,11 +79,17 @@ func (s *RecursiveScanner) scanStateInit() (*token.TokenContext, error) {
 	case IsUpper(c) || IsLower(c) || c == '_':
 		elem, err = s.scanElementIdentifierOrKeyword()
 
-	case IsPunct(c) && c != '"':
+	case IsIdentifierPrefix(c) && s.isIdentifierStart(1):
+		elem, err = s.scanElementIdentifierOrKeyword()
+
+	case IsPunct(c) && c != '"' && c != '`':
 		elem, err = s.scanStatePunctuation()
 
 	case c == '"':
 		elem, err = s.scanStateString()
+
+	case c == '`':
+		elem, err = s.scanStateRawString()
 	}
 
 	return elem, err
@@ -136,8 +158,21 @@ func (s *RecursiveScanner) scanElementFloat() (*token.TokenContext, error) {
 	return elem, nil
 }
 
+func (s *RecursiveScanner) isIdentifierStart(offset int) bool {
+	if s.CharsLeft() <= offset {
+		return false
+	}
+
+	c := s.PeekChar(offset)
+	return IsUpper(c) || IsLower(c) || c == '_'
+}
+
 func (s *RecursiveScanner) scanElementIdentifierOrKeyword() (*token.TokenContext, error) {
 	s.StartToken()
+	if IsIdentifierPrefix(s.Current()) {
+		s.Shift(1)
+	}
+
 	for !s.EOF() {
 		c := s.Current()
 		if IsUpper(c) || IsLower(c) || IsDigit(c) || c == '_' {
@@ -148,6 +183,12 @@ func (s *RecursiveScanner) scanElementIdentifierOrKeyword() (*token.TokenContext
 		}
 	}
 
+	// Suffix '!' is not taken in "a != b".
+	if !s.EOF() && IsIdentifierSuffix(s.Current()) &&
+		!(s.Current() == '!' && s.CharsLeft() > 1 && s.PeekChar(1) == '=') {
+		s.Shift(1)
+	}
+
 	elem := s.FinishToken(token.Identifier)
 	elem.Token = token.CheckKeywordToken(elem.Content)
 
@@ -158,31 +199,69 @@ func (s *RecursiveScanner) scanStateString() (*token.TokenContext, error) {
 	s.StartToken()
 	s.Shift(1) // include the first '"'
 
-StringLoop:
+	if err := s.scanStringBody(); err != nil {
+		return nil, err
+	}
+
+	elem := s.FinishToken(token.String)
+	return elem, nil
+}
+
+// scanStateRawString scans a string quoted by '`', which may across lines and
+// has no escape sequences.
+func (s *RecursiveScanner) scanStateRawString() (*token.TokenContext, error) {
+	s.StartToken()
+	s.Shift(1) // include the first '`'
+
+	for !s.EOF() {
+		c := s.Current()
+		s.Shift(1)
+		if c == '`' {
+			elem := s.FinishToken(token.String)
+			return elem, nil
+		}
+	}
+
+	elem := s.FinishToken(token.Illegal)
+	return nil, NewLexicalError(elem, "unterminated raw string")
+}
+
+// scanStringBody scans a string after the first '"', until the closing '"'.
+// Embedded expressions of string interpolation are kept in the string, and
+// parsed by parser.
+func (s *RecursiveScanner) scanStringBody() error {
 	for !s.EOF() {
 		c := s.Current()
 		s.Shift(1)
 
 		switch c {
 		case '"':
-			break StringLoop
+			return nil
+
+		case '$':
+			if !s.EOF() && s.Current() == '{' {
+				s.Shift(1)
+				if err := s.scanInterpolation(); err != nil {
+					return err
+				}
+			}
 
 		case '\\':
 			if s.EOF() {
 				err := s.RejectError(1, "unexpected EOF")
-				return nil, err
+				return err
 			}
 
 			n := s.Current()
 			switch n {
-			case '\\', 'n', 'r', 't', '"':
+			case '\\', 'n', 'r', 't', '"', '$':
 				s.Shift(1)
 
 			case 'x':
 				s.Shift(1)
 				if charsLeft := s.CharsLeft(); charsLeft < 2 {
 					err := s.RejectError(charsLeft+1, "insufficient characters for escape sequence")
-					return nil, err
+					return err
 				}
 
 				n1 := s.Current()
@@ -192,18 +271,54 @@ StringLoop:
 
 				} else {
 					err := s.RejectError(2, "invalid escape sequence: \\x%c%c", n1, n2)
-					return nil, err
+					return err
 				}
 
 			default:
 				err := s.RejectError(1, "invalid escape sequence: \\%c", n)
-				return nil, err
+				return err
 			}
 		}
 	}
 
-	elem := s.FinishToken(token.String)
-	return elem, nil
+	return nil
+}
+
+// scanInterpolation scans an embedded expression after "${", until the matched
+// '}'. Strings in the expression may contain braces and interpolations too.
+func (s *RecursiveScanner) scanInterpolation() error {
+	depth := 1
+	for !s.EOF() {
+		c := s.Current()
+		s.Shift(1)
+
+		switch c {
+		case '{':
+			depth++
+
+		case '}':
+			depth--
+			if depth == 0 {
+				return nil
+			}
+
+		case '"':
+			if err := s.scanStringBody(); err != nil {
+				return err
+			}
+
+		case '`':
+			for !s.EOF() && s.Current() != '`' {
+				s.Shift(1)
+			}
+
+			if !s.EOF() {
+				s.Shift(1)
+			}
+		}
+	}
+
+	return nil
 }
 
 func (s *RecursiveScanner) makeForwardLexicalElement(length int) *token.TokenContext {
@@ -227,11 +342,13 @@ func (s *RecursiveScanner) tryScanPunctuations(ps ...string) *token.TokenContext
 }
 
 var multiBytesPunctutations = []string{
-	token.SEQ, token.SAssign,
+	token.SEQ, token.SArrow, token.SAssign,
 	token.SNE, token.SBang,
 	token.SGE, token.SGT,
 	token.SLE, token.SLT,
 	token.SDualColon, token.SColon,
+	token.SAND, token.SOR,
+	token.SEllipsis,
 }
 
 func (s *RecursiveScanner) scanStatePunctuation() (*token.TokenContext, error) {
@@ -242,6 +359,10 @@ func (s *RecursiveScanner) scanStatePunctuation() (*token.TokenContext, error) {
 		return s.scanStateComment()
 	}
 
+	if s.Peek("/*") {
+		return s.scanStateBlockComment()
+	}
+
 	c := s.Current()
 	elem = s.tryScanPunctuations(multiBytesPunctutations...)
 	if elem != nil {
@@ -276,6 +397,36 @@ func (s *RecursiveScanner) scanStateComment() (*token.TokenContext, error) {
 	return elem, nil
 }
 
+// scanStateBlockComment scans a comment in "/*" and "*/", which may across lines
+// and contain nested block comments.
+func (s *RecursiveScanner) scanStateBlockComment() (*token.TokenContext, error) {
+	s.StartToken()
+	s.Shift(2) // shift the first '/*'
+
+	depth := 1
+	for !s.EOF() {
+		switch {
+		case s.Peek("/*"):
+			s.Shift(2)
+			depth++
+
+		case s.Peek("*/"):
+			s.Shift(2)
+			depth--
+			if depth == 0 {
+				elem := s.FinishToken(token.Comment)
+				return elem, nil
+			}
+
+		default:
+			s.Shift(1)
+		}
+	}
+
+	elem := s.FinishToken(token.Illegal)
+	return nil, NewLexicalError(elem, "unterminated block comment")
+}
+
 func (s *RecursiveScanner) ScanEOF() *token.TokenContext {
 	s.StartToken()
 	elem := s.FinishToken(token.EOF)
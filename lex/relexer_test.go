@@ -140,6 +140,38 @@ func TestScanIdentifierAndKeyworkd(t *testing.T) {
 	checkTokenScan(t, lex, expected)
 }
 
+func TestScanIdentifierWithPrefixAndSuffix(t *testing.T) {
+	code := `@mod $g_1 empty? push! @ok?
+		a!=b c! = d $ @1`
+
+	lex := NewRecursiveScanner("testcase")
+	lex.SetContent([]byte(code))
+
+	expected := []expectedTokenInfo{
+		{token.Identifier, "@mod", 1, 1},
+		{token.Identifier, "$g_1", 1, 6},
+		{token.Identifier, "empty?", 1, 11},
+		{token.Identifier, "push!", 1, 18},
+		{token.Identifier, "@ok?", 1, 24},
+		{token.Identifier, "a", 2, 3},
+		{token.NE, "!=", 2, 4},
+		{token.Identifier, "b", 2, 6},
+		{token.Identifier, "c!", 2, 8},
+		{token.Assign, "=", 2, 11},
+		{token.Identifier, "d", 2, 13},
+	}
+
+	checkTokenScan(t, lex, expected)
+
+	// Prefix without identifier is not accepted.
+	for i := 0; i < 2; i++ {
+		if elem, err := lex.Scan(); err == nil {
+			t.Errorf("Scan() should fail, but got %v", elem)
+		}
+		lex.Shift(1)
+	}
+}
+
 // func TestScannerAppend(t *testing.T) {
 // 	lex := NewRecursiveScanner("testcase")
 // 	lex.Append([]byte("foobar"))
@@ -156,13 +188,15 @@ func TestScanIdentifierAndKeyworkd(t *testing.T) {
 func TestScanStrings(t *testing.T) {
 	code := ` "foobar"
 		"foo\nbar\""
-		"c+\x2b"`
+		"c+\x2b"
+		"\$a ${ {"k": "}"}.k + "${b}" } $c"`
 
 	expected := []expectedTokenInfo{
 		{token.String, "\"foobar\"", 1, 2},
 		{token.String, "\"foo\\nbar\\\"\"", 2, 3},
 		{token.String, "\"c+\\x2b\"", 3, 3},
-		{token.EOF, "", 3, 11},
+		{token.String, "\"\\$a ${ {\"k\": \"}\"}.k + \"${b}\" } $c\"", 4, 3},
+		{token.EOF, "", 4, 38},
 	}
 
 	lex := NewRecursiveScanner("testcase")
@@ -281,8 +315,8 @@ func TestStringEscapeAtTheEnd2(t *testing.T) {
 
 func TestScanPunctuations(t *testing.T) {
 	code := `(){}[];,.
-	=== !=== <= >=
-	/-*+`
+	=== !=== <= >= =>
+	/-*+ &&& ||| ....`
 
 	lex := NewRecursiveScanner("testcase")
 	lex.SetContent([]byte(code))
@@ -303,11 +337,18 @@ func TestScanPunctuations(t *testing.T) {
 		{token.EQ, "==", 2, 8},
 		{token.LE, "<=", 2, 11},
 		{token.GE, ">=", 2, 14},
+		{token.Arrow, "=>", 2, 17},
 		{token.Slash, "/", 3, 2},
 		{token.Minus, "-", 3, 3},
 		{token.Asterisk, "*", 3, 4},
 		{token.Plus, "+", 3, 5},
-		{token.EOF, "", 3, 6},
+		{token.AND, "&&", 3, 7},
+		{token.BITAND, "&", 3, 9},
+		{token.OR, "||", 3, 11},
+		{token.BITOR, "|", 3, 13},
+		{token.Ellipsis, "...", 3, 15},
+		{token.Period, ".", 3, 18},
+		{token.EOF, "", 3, 19},
 	}
 
 	checkTokenScan(t, lex, expected)
@@ -361,6 +402,133 @@ func TestScanComment(t *testing.T) {
 	checkTokenScan(t, lex, expected)
 }
 
+func TestScanBlockComment(t *testing.T) {
+	code := strings.Join([]string{
+		`foo /* a */ bar /* multiple`,
+		`  lines /* nested */`,
+		`*/ + /**/`,
+		`	baz`,
+	}, "\n")
+
+	lex := NewRecursiveScanner("testcase")
+	lex.SetContent([]byte(code))
+
+	expected := []expectedTokenInfo{
+		{token.Identifier, "foo", 1, 1},
+		{token.Comment, "/* a */", 1, 5},
+		{token.Identifier, "bar", 1, 13},
+		{token.Comment, "/* multiple\n  lines /* nested */\n*/", 1, 17},
+		{token.Plus, "+", 3, 4},
+		{token.Comment, "/**/", 3, 6},
+		{token.Identifier, "baz", 4, 2},
+		{token.EOF, "", 4, 5},
+	}
+
+	checkTokenScan(t, lex, expected)
+}
+
+func TestScanRawString(t *testing.T) {
+	code := strings.Join([]string{
+		"a = `raw \\n ${b}",
+		"\t\"lines\"` + `` c",
+	}, "\n")
+
+	lex := NewRecursiveScanner("testcase")
+	lex.SetContent([]byte(code))
+
+	expected := []expectedTokenInfo{
+		{token.Identifier, "a", 1, 1},
+		{token.Assign, "=", 1, 3},
+		{token.String, "`raw \\n ${b}\n\t\"lines\"`", 1, 5},
+		{token.Plus, "+", 2, 11},
+		{token.String, "``", 2, 13},
+		{token.Identifier, "c", 2, 16},
+	}
+
+	checkTokenScan(t, lex, expected)
+}
+
+func TestMultiLineTokenHighlight(t *testing.T) {
+	code := strings.Join([]string{
+		"let s = `first",
+		"\tsecond",
+		"third`;",
+	}, "\n")
+
+	lex := NewRecursiveScanner("testcase")
+	lex.SetContent([]byte(code))
+
+	var elem *token.TokenContext
+	for i := 0; i < 4; i++ {
+		var err error
+		if elem, err = lex.Scan(); err != nil {
+			t.Fatalf("Scan() failed: %v", err)
+		}
+	}
+
+	expected := strings.Join([]string{
+		"let s = `first",
+		"        ^^^^^^",
+		"\tsecond",
+		"^^^^^^^",
+		"third`;",
+		"^^^^^^",
+		"invalid string",
+		"  at testcase:1:9",
+	}, "\n")
+
+	got := elem.ToContext().Message("invalid string")
+	if got != expected {
+		t.Errorf("got wrong message, got:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestScanUnterminatedError(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected []string
+	}{
+		{
+			"a /* b /* c */\n d",
+			[]string{
+				"a /* b /* c */",
+				"  ^^^^^^^^^^^^",
+				" d",
+				"^^",
+				"unterminated block comment",
+				"  at testcase:1:3",
+			},
+		},
+		{
+			"b + `xy",
+			[]string{
+				"b + `xy",
+				"    ^^^",
+				"    unterminated raw string",
+				"  at testcase:1:5",
+			},
+		},
+	}
+
+	for _, c := range tests {
+		lex := NewRecursiveScanner("testcase")
+		lex.SetContent([]byte(c.code))
+
+		var err error
+		for err == nil {
+			var elem *token.TokenContext
+			if elem, err = lex.Scan(); err == nil && elem.Token == token.EOF {
+				t.Fatalf("Scan() should fail: %s", c.code)
+			}
+		}
+
+		expected := strings.Join(c.expected, "\n")
+		if err.Error() != expected {
+			t.Errorf("got wrong error message, got:\n%s\nexpected:\n%s", err, expected)
+		}
+	}
+}
+
 func TestEOFHighlight(t *testing.T) {
 	code := strings.Join([]string{
 		`foo`,
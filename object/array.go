@@ -103,3 +103,33 @@ func (a *ArrayObject) OnIndex(o Object) (Object, bool) {
 
 	return r, ok
 }
+
+// OnAssignIndex sets element of index, which must be in range of the array.
+func (a *ArrayObject) OnAssignIndex(index Object, value Object) bool {
+	i, ok := index.(*IntegerObject)
+	if !ok || i.Value < 0 || i.Value >= int64(len(a.Elements)) {
+		return false
+	}
+
+	a.Elements[i.Value] = value
+	return true
+}
+
+// OnSlice makes a slice of the array, which shares elements with the array.
+func (a *ArrayObject) OnSlice(low Object, high Object) (Object, bool) {
+	l, h, ok := sliceBounds(low, high, len(a.Elements))
+	if !ok {
+		return nil, false
+	}
+
+	// Capacity is limited, so the slice never writes beyond its end.
+	r := &ArrayObject{
+		Elements: a.Elements[l:h:h],
+	}
+
+	return r, true
+}
+
+func (a *ArrayObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(a, name)
+}
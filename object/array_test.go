@@ -71,6 +71,20 @@ func TestArrayObjectEvaluation(t *testing.T) {
 				NewInteger(4),
 			}))).
 			expect(NewBoolean(false), true),
+		evalTest("ARRAY[1, 2, 3] == ARRAY[1.0, 2, 3.0]").
+			call(a.OnInfix(token.EQ, NewArray([]Object{
+				NewFloat(1.0),
+				NewInteger(2),
+				NewFloat(3.0),
+			}))).
+			expect(NewBoolean(true), true),
+		evalTest("ARRAY[1, 2, 3] == ARRAY[1, 2, 3.5]").
+			call(a.OnInfix(token.EQ, NewArray([]Object{
+				NewInteger(1),
+				NewInteger(2),
+				NewFloat(3.5),
+			}))).
+			expect(NewBoolean(false), true),
 		evalTest("ARRAY[1, 2, 3] == INTEGER(42)").
 			call(a.OnInfix(token.EQ, NewInteger(42))).
 			expect(NewBoolean(false), true),
@@ -115,3 +129,78 @@ func TestArrayOnIndexEvalutation(t *testing.T) {
 
 	testObjectEvaluation(t, tests)
 }
+
+func TestArrayOnAssignIndex(t *testing.T) {
+	a := NewArray([]Object{
+		NewInteger(1),
+		NewInteger(2),
+		NewInteger(3),
+	})
+
+	tests := []struct {
+		index    Object
+		expected bool
+	}{
+		{NewInteger(0), true},
+		{NewInteger(2), true},
+		{NewInteger(3), false},
+		{NewInteger(-1), false},
+		{NewString("0"), false},
+	}
+
+	for _, c := range tests {
+		if got := a.OnAssignIndex(c.index, NewInteger(42)); got != c.expected {
+			t.Errorf("ARRAY[%s] = 42 expected %v, got %v", c.index.Inspect(), c.expected, got)
+		}
+	}
+
+	if a.Inspect() != "[42, 2, 42]" {
+		t.Errorf("wrong array after assignment, got %s", a.Inspect())
+	}
+}
+
+func TestArrayOnSlice(t *testing.T) {
+	a := NewArray([]Object{
+		NewInteger(1),
+		NewInteger(2),
+		NewInteger(3),
+		NewInteger(4),
+	})
+	null := NewNull()
+
+	tests := []struct {
+		low      Object
+		high     Object
+		expected string
+	}{
+		{NewInteger(1), NewInteger(3), "[2, 3]"},
+		{null, NewInteger(-1), "[1, 2, 3]"},
+		{NewInteger(-1), null, "[4]"},
+		{null, null, "[1, 2, 3, 4]"},
+		{NewInteger(3), NewInteger(1), "[]"},
+		{NewInteger(-10), NewInteger(10), "[1, 2, 3, 4]"},
+	}
+
+	for _, c := range tests {
+		got, ok := a.OnSlice(c.low, c.high)
+		if !ok {
+			t.Fatalf("ARRAY[%s:%s] is not accepted", c.low.Inspect(), c.high.Inspect())
+		}
+
+		if got.Inspect() != c.expected {
+			t.Errorf("ARRAY[%s:%s] expected %s, got %s",
+				c.low.Inspect(), c.high.Inspect(), c.expected, got.Inspect())
+		}
+	}
+
+	if _, ok := a.OnSlice(NewFloat(1.0), null); ok {
+		t.Errorf("ARRAY[FLOAT:NULL] is accepted")
+	}
+
+	// Slice shares elements with the array.
+	s, _ := a.OnSlice(NewInteger(1), NewInteger(3))
+	s.OnAssignIndex(NewInteger(0), NewInteger(20))
+	if a.Inspect() != "[1, 20, 3, 4]" {
+		t.Errorf("array is not changed by its slice, got %s", a.Inspect())
+	}
+}
@@ -0,0 +1,69 @@
+package object
+
+import (
+	"github.com/flily/macaque-lang/token"
+)
+
+// CellObject holds value of a mutable variable. Closures capture the cell
+// rather than the value, so assignments are shared by all of them. Cells are
+// never visible to user code.
+type CellObject struct {
+	Value Object
+}
+
+func NewCell(value Object) *CellObject {
+	c := &CellObject{
+		Value: value,
+	}
+
+	return c
+}
+
+func (c *CellObject) Type() ObjectType {
+	return ObjectTypeCell
+}
+
+func (c *CellObject) Inspect() string {
+	return "cell(" + c.Value.Inspect() + ")"
+}
+
+func (c *CellObject) Hashable() bool {
+	return false
+}
+
+func (c *CellObject) HashKey() interface{} {
+	return nil
+}
+
+func (c *CellObject) EqualTo(o Object) bool {
+	switch v := o.(type) {
+	case *CellObject:
+		return c == v
+	}
+
+	return false
+}
+
+func (c *CellObject) OnPrefix(t token.Token) (Object, bool) {
+	return nil, false
+}
+
+func (c *CellObject) OnInfix(t token.Token, o Object) (Object, bool) {
+	return nil, false
+}
+
+func (c *CellObject) OnIndex(o Object) (Object, bool) {
+	return nil, false
+}
+
+func (c *CellObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (c *CellObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+func (c *CellObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(c, name)
+}
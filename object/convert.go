@@ -0,0 +1,516 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Go values are converted to and from objects by rules below.
+//
+//	Go                        | Object
+//	--------------------------+-----------------------------------
+//	nil, nil pointer          | NULL
+//	bool                      | BOOLEAN
+//	int, int8 ... uint64      | INTEGER
+//	float32, float64          | FLOAT, INTEGER is accepted by ToGo
+//	string                    | STRING
+//	slice, array              | ARRAY
+//	map                       | HASH
+//	struct                    | HASH, keys are names of fields
+//	Object                    | the object itself
+//
+// Name of a struct field can be changed by tag `macaque:"name"`, and the field
+// is skipped with tag `macaque:"-"`. Unexported fields are always skipped. Go
+// values referring to themselves by pointers, maps or slices are not converted.
+
+const convertTagName = "macaque"
+
+var typeOfObject = reflect.TypeOf((*Object)(nil)).Elem()
+
+// ConvertError is returned when a value can not be converted. Path tells where
+// the value is, in form like `.servers[0].port`, and it is empty for the value
+// itself.
+type ConvertError struct {
+	Path   string
+	Reason string
+}
+
+func (e *ConvertError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Reason
+	}
+
+	return fmt.Sprintf("%s at %s", e.Reason, e.Path)
+}
+
+func convertError(path string, format string, args ...interface{}) error {
+	e := &ConvertError{
+		Path:   path,
+		Reason: fmt.Sprintf(format, args...),
+	}
+
+	return e
+}
+
+// FromGo converts Go value v to an object.
+func FromGo(v interface{}) (Object, error) {
+	if v == nil {
+		return objectNull, nil
+	}
+
+	return fromGoValue(reflect.ValueOf(v), "", make(map[visitKey]bool))
+}
+
+// visitKey identifies a pointer, map or slice being converted. Pointers to a
+// struct and to its first field have the same address, so type is a part.
+type visitKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// visit marks reference v as being converted, and returns an error if it is
+// being converted already, which means v refers to itself.
+func visit(visiting map[visitKey]bool, v reflect.Value, path string) (visitKey, error) {
+	key := visitKey{typ: v.Type(), ptr: v.Pointer()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+
+	if visiting[key] {
+		return key, convertError(path, "Go %s value refers to itself", v.Type())
+	}
+
+	visiting[key] = true
+	return key, nil
+}
+
+func fromGoValue(v reflect.Value, path string, visiting map[visitKey]bool) (Object, error) {
+	if v.Type().Implements(typeOfObject) {
+		k := v.Kind()
+		if (k == reflect.Pointer || k == reflect.Interface) && v.IsNil() {
+			return objectNull, nil
+		}
+
+		return v.Interface().(Object), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return objectNull, nil
+		}
+
+		key, err := visit(visiting, v, path)
+		if err != nil {
+			return nil, err
+		}
+
+		defer delete(visiting, key)
+		return fromGoValue(v.Elem(), path, visiting)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return objectNull, nil
+		}
+
+		return fromGoValue(v.Elem(), path, visiting)
+
+	case reflect.Bool:
+		return NewBoolean(v.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewInteger(v.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := v.Uint()
+		if n > math.MaxInt64 {
+			return nil, convertError(path, "Go %s value %d overflows INTEGER", v.Type(), n)
+		}
+
+		return NewInteger(int64(n)), nil
+
+	case reflect.Float32, reflect.Float64:
+		return NewFloat(v.Float()), nil
+
+	case reflect.String:
+		return NewString(v.String()), nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Array {
+			return fromGoSlice(v, path, visiting)
+		}
+
+		if v.IsNil() {
+			return objectNull, nil
+		}
+
+		key, err := visit(visiting, v, path)
+		if err != nil {
+			return nil, err
+		}
+
+		defer delete(visiting, key)
+		return fromGoSlice(v, path, visiting)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return objectNull, nil
+		}
+
+		key, err := visit(visiting, v, path)
+		if err != nil {
+			return nil, err
+		}
+
+		defer delete(visiting, key)
+		return fromGoMap(v, path, visiting)
+
+	case reflect.Struct:
+		return fromGoStruct(v, path, visiting)
+	}
+
+	return nil, convertError(path, "Go %s can not be converted", v.Type())
+}
+
+func fromGoSlice(v reflect.Value, path string, visiting map[visitKey]bool) (Object, error) {
+	elements := make([]Object, v.Len())
+	for i := range elements {
+		e, err := fromGoValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), visiting)
+		if err != nil {
+			return nil, err
+		}
+
+		elements[i] = e
+	}
+
+	return NewArray(elements), nil
+}
+
+// fromGoMap converts a map to a hash. As order of map is random, pairs are
+// sorted by keys to make the hash stable.
+func fromGoMap(v reflect.Value, path string, visiting map[visitKey]bool) (Object, error) {
+	pairs := make([]HashPair, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := fromGoValue(iter.Key(), path, visiting)
+		if err != nil {
+			return nil, err
+		}
+
+		if !key.Hashable() {
+			return nil, convertError(path, "%s can not be key of HASH", key.Type())
+		}
+
+		value, err := fromGoValue(iter.Value(), fmt.Sprintf("%s[%s]", path, key.Inspect()), visiting)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, HashPair{Key: key, Value: value})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Key.Inspect() < pairs[j].Key.Inspect()
+	})
+
+	return NewHash(pairs), nil
+}
+
+func fromGoStruct(v reflect.Value, path string, visiting map[visitKey]bool) (Object, error) {
+	t := v.Type()
+	pairs := make([]HashPair, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := fieldName(t.Field(i))
+		if !ok {
+			continue
+		}
+
+		value, err := fromGoValue(v.Field(i), path+"."+name, visiting)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, HashPair{Key: NewString(name), Value: value})
+	}
+
+	return NewHash(pairs), nil
+}
+
+// fieldName returns key of struct field f in hash, and false if f is skipped.
+func fieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+
+	tag := f.Tag.Get(convertTagName)
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+
+	switch tag {
+	case "-":
+		return "", false
+
+	case "":
+		return f.Name, true
+	}
+
+	return tag, true
+}
+
+// ToGo converts object o to Go value, and stores it in the value pointed by
+// target. Null is converted to zero value of the target type.
+func ToGo(o Object, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return convertError("", "target must be a non-nil pointer, but got %T", target)
+	}
+
+	return toGoValue(o, v.Elem(), "")
+}
+
+func toGoValue(o Object, v reflect.Value, path string) error {
+	t := v.Type()
+	if t.Implements(typeOfObject) && reflect.TypeOf(o).AssignableTo(t) {
+		v.Set(reflect.ValueOf(o))
+		return nil
+	}
+
+	if u, ok := o.(*UserValue); ok {
+		value := reflect.ValueOf(u.Value)
+		if u.Value != nil && value.Type().AssignableTo(t) {
+			v.Set(value)
+			return nil
+		}
+	}
+
+	if o.Type() == ObjectTypeNull {
+		v.Set(reflect.Zero(t))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(t.Elem())
+		if err := toGoValue(o, p.Elem(), path); err != nil {
+			return err
+		}
+
+		v.Set(p)
+		return nil
+
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			break
+		}
+
+		value, err := toGoInterface(o, path)
+		if err != nil {
+			return err
+		}
+
+		if value == nil {
+			v.Set(reflect.Zero(t))
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+		return nil
+
+	case reflect.Bool:
+		if b, ok := o.(*BooleanObject); ok {
+			v.SetBool(b.Value)
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := o.(*IntegerObject); ok {
+			if v.OverflowInt(n.Value) {
+				return convertError(path, "INTEGER %d overflows Go %s", n.Value, t)
+			}
+
+			v.SetInt(n.Value)
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, ok := o.(*IntegerObject); ok {
+			if n.Value < 0 || v.OverflowUint(uint64(n.Value)) {
+				return convertError(path, "INTEGER %d overflows Go %s", n.Value, t)
+			}
+
+			v.SetUint(uint64(n.Value))
+			return nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch n := o.(type) {
+		case *FloatObject:
+			v.SetFloat(n.Value)
+			return nil
+
+		case *IntegerObject:
+			v.SetFloat(float64(n.Value))
+			return nil
+		}
+
+	case reflect.String:
+		if s, ok := o.(*StringObject); ok {
+			v.SetString(s.Value)
+			return nil
+		}
+
+	case reflect.Slice:
+		if a, ok := o.(*ArrayObject); ok {
+			s := reflect.MakeSlice(t, len(a.Elements), len(a.Elements))
+			if err := toGoElements(a, s, path); err != nil {
+				return err
+			}
+
+			v.Set(s)
+			return nil
+		}
+
+	case reflect.Array:
+		if a, ok := o.(*ArrayObject); ok {
+			if len(a.Elements) > v.Len() {
+				return convertError(path, "ARRAY of %d elements overflows Go %s", len(a.Elements), t)
+			}
+
+			v.Set(reflect.Zero(t))
+			return toGoElements(a, v, path)
+		}
+
+	case reflect.Map:
+		if h, ok := o.(*HashObject); ok {
+			return toGoMap(h, v, path)
+		}
+
+	case reflect.Struct:
+		if h, ok := o.(*HashObject); ok {
+			return toGoStruct(h, v, path)
+		}
+	}
+
+	return convertError(path, "%s can not be converted to Go %s", o.Type(), t)
+}
+
+func toGoElements(a *ArrayObject, v reflect.Value, path string) error {
+	for i, e := range a.Elements {
+		if err := toGoValue(e, v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toGoMap(h *HashObject, v reflect.Value, path string) error {
+	t := v.Type()
+	m := reflect.MakeMapWithSize(t, len(h.Elements))
+	for _, e := range h.Elements {
+		key := reflect.New(t.Key()).Elem()
+		if err := toGoValue(e.Key, key, path); err != nil {
+			return err
+		}
+
+		value := reflect.New(t.Elem()).Elem()
+		if err := toGoValue(e.Value, value, fmt.Sprintf("%s[%s]", path, e.Key.Inspect())); err != nil {
+			return err
+		}
+
+		m.SetMapIndex(key, value)
+	}
+
+	v.Set(m)
+	return nil
+}
+
+// toGoStruct sets fields of struct by values in hash with the same keys. Fields
+// not found in hash are kept, and keys not found in struct are ignored.
+func toGoStruct(h *HashObject, v reflect.Value, path string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := fieldName(t.Field(i))
+		if !ok {
+			continue
+		}
+
+		pair, found := h.Map[NewString(name).HashKey()]
+		if !found {
+			continue
+		}
+
+		if err := toGoValue(pair.Value, v.Field(i), path+"."+name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toGoInterface converts o to a natural Go value, for targets like interface{}.
+// Hash is converted to map[string]interface{} if all of its keys are strings,
+// or map[interface{}]interface{} otherwise.
+func toGoInterface(o Object, path string) (interface{}, error) {
+	switch v := o.(type) {
+	case *NullObject:
+		return nil, nil
+
+	case *BooleanObject:
+		return v.Value, nil
+
+	case *IntegerObject:
+		return v.Value, nil
+
+	case *FloatObject:
+		return v.Value, nil
+
+	case *StringObject:
+		return v.Value, nil
+
+	case *UserValue:
+		return v.Value, nil
+
+	case *ArrayObject:
+		result := make([]interface{}, len(v.Elements))
+		for i, e := range v.Elements {
+			r, err := toGoInterface(e, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = r
+		}
+
+		return result, nil
+
+	case *HashObject:
+		return toGoInterfaceMap(v, path)
+	}
+
+	return nil, convertError(path, "%s can not be converted to Go value", o.Type())
+}
+
+func toGoInterfaceMap(h *HashObject, path string) (interface{}, error) {
+	stringKeys := true
+	for _, e := range h.Elements {
+		if e.Key.Type() != ObjectTypeString {
+			stringKeys = false
+			break
+		}
+	}
+
+	if stringKeys {
+		var result map[string]interface{}
+		err := toGoValue(h, reflect.ValueOf(&result).Elem(), path)
+		return result, err
+	}
+
+	var result map[interface{}]interface{}
+	err := toGoValue(h, reflect.ValueOf(&result).Elem(), path)
+	return result, err
+}
@@ -0,0 +1,284 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testServer struct {
+	Host    string `macaque:"host"`
+	Port    int    `macaque:"port"`
+	Weight  float64
+	Tags    []string       `macaque:"tags"`
+	Labels  map[string]int `macaque:"labels"`
+	Backup  *testServer    `macaque:"backup"`
+	Secret  string         `macaque:"-"`
+	private int
+}
+
+func TestFromGo(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected Object
+	}{
+		{nil, NewNull()},
+		{true, NewBoolean(true)},
+		{42, NewInteger(42)},
+		{uint8(7), NewInteger(7)},
+		{1.5, NewFloat(1.5)},
+		{"abc", NewString("abc")},
+		{[]int{1, 2}, NewArray([]Object{NewInteger(1), NewInteger(2)})},
+		{[2]string{"a", "b"}, NewArray([]Object{NewString("a"), NewString("b")})},
+		{[]int(nil), NewNull()},
+		{(*int)(nil), NewNull()},
+		{NewInteger(3), NewInteger(3)},
+		{[]interface{}{1, "a", nil}, NewArray([]Object{NewInteger(1), NewString("a"), NewNull()})},
+		{
+			map[string]int{"b": 2, "a": 1},
+			NewHash([]HashPair{
+				{NewString("a"), NewInteger(1)},
+				{NewString("b"), NewInteger(2)},
+			}),
+		},
+		{
+			&testServer{Host: "localhost", Port: 80, Secret: "s", private: 1},
+			NewHash([]HashPair{
+				{NewString("host"), NewString("localhost")},
+				{NewString("port"), NewInteger(80)},
+				{NewString("Weight"), NewFloat(0)},
+				{NewString("tags"), NewNull()},
+				{NewString("labels"), NewNull()},
+				{NewString("backup"), NewNull()},
+			}),
+		},
+	}
+
+	for _, c := range tests {
+		got, err := FromGo(c.value)
+		if err != nil {
+			t.Fatalf("FromGo(%#v) got error: %s", c.value, err)
+		}
+
+		if !got.EqualTo(c.expected) {
+			t.Errorf("FromGo(%#v) expect %s, got %s", c.value, c.expected.Inspect(), got.Inspect())
+		}
+	}
+}
+
+func TestFromGoError(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{
+			uint64(1 << 63),
+			"Go uint64 value 9223372036854775808 overflows INTEGER",
+		},
+		{
+			[]interface{}{1, func() {}},
+			"Go func() can not be converted at [1]",
+		},
+		{
+			map[string]interface{}{"f": make(chan int)},
+			"Go chan int can not be converted at [f]",
+		},
+		{
+			map[interface{}]int{[1]int{1}: 1},
+			"ARRAY can not be key of HASH",
+		},
+	}
+
+	for _, c := range tests {
+		_, err := FromGo(c.value)
+		if err == nil {
+			t.Fatalf("FromGo(%#v) expect error", c.value)
+		}
+
+		if err.Error() != c.expected {
+			t.Errorf("FromGo(%#v) expect error %q, got %q", c.value, c.expected, err)
+		}
+	}
+}
+
+type testNode struct {
+	Next *testNode
+}
+
+func TestFromGoCycle(t *testing.T) {
+	var node testNode
+	node.Next = &node
+
+	list := []interface{}{1, nil}
+	list[1] = list
+
+	hash := map[string]interface{}{}
+	hash["self"] = hash
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"pointer", &node, "Go *object.testNode value refers to itself at .Next"},
+		{"struct", node, "Go *object.testNode value refers to itself at .Next.Next"},
+		{"slice", list, "Go []interface {} value refers to itself at [1]"},
+		{"map", hash, "Go map[string]interface {} value refers to itself at [self]"},
+	}
+
+	for _, c := range tests {
+		_, err := FromGo(c.value)
+		if err == nil {
+			t.Fatalf("FromGo(%s) expect error", c.name)
+		}
+
+		if _, ok := err.(*ConvertError); !ok || err.Error() != c.expected {
+			t.Errorf("FromGo(%s) expect error %q, got %q", c.name, c.expected, err)
+		}
+	}
+
+	// Values referred more than once without cycle are converted.
+	shared := &testServer{Host: "b"}
+	got, err := FromGo([]*testServer{shared, shared})
+	if err != nil {
+		t.Fatalf("FromGo(shared) error: %s", err)
+	}
+
+	if n := len(got.(*ArrayObject).Elements); n != 2 {
+		t.Errorf("FromGo(shared) expect 2 elements, got %d", n)
+	}
+}
+
+func TestToGo(t *testing.T) {
+	config := NewHash([]HashPair{
+		{NewString("host"), NewString("localhost")},
+		{NewString("port"), NewInteger(8080)},
+		{NewString("Weight"), NewInteger(2)},
+		{NewString("tags"), NewArray([]Object{NewString("a"), NewString("b")})},
+		{NewString("labels"), NewHash([]HashPair{{NewString("x"), NewInteger(1)}})},
+		{NewString("backup"), NewHash([]HashPair{{NewString("port"), NewInteger(81)}})},
+		{NewString("Secret"), NewString("s")},
+		{NewString("unknown"), NewInteger(1)},
+	})
+
+	var server testServer
+	if err := ToGo(config, &server); err != nil {
+		t.Fatalf("ToGo got error: %s", err)
+	}
+
+	expected := testServer{
+		Host:   "localhost",
+		Port:   8080,
+		Weight: 2,
+		Tags:   []string{"a", "b"},
+		Labels: map[string]int{"x": 1},
+		Backup: &testServer{Port: 81},
+	}
+
+	if !reflect.DeepEqual(server, expected) {
+		t.Errorf("ToGo expect %+v, got %+v", expected, server)
+	}
+
+	var natural interface{}
+	value := NewArray([]Object{
+		NewInteger(1),
+		NewNull(),
+		NewHash([]HashPair{{NewString("a"), NewFloat(1.5)}}),
+		NewHash([]HashPair{{NewInteger(1), NewBoolean(true)}}),
+	})
+	if err := ToGo(value, &natural); err != nil {
+		t.Fatalf("ToGo got error: %s", err)
+	}
+
+	expectedAny := []interface{}{
+		int64(1),
+		nil,
+		map[string]interface{}{"a": 1.5},
+		map[interface{}]interface{}{int64(1): true},
+	}
+	if !reflect.DeepEqual(natural, expectedAny) {
+		t.Errorf("ToGo expect %#v, got %#v", expectedAny, natural)
+	}
+
+	var o Object
+	if err := ToGo(value, &o); err != nil || o != value {
+		t.Errorf("ToGo to Object got %v, %v", o, err)
+	}
+
+	n := 3
+	var p *int
+	if err := ToGo(NewUserValue("n", &n, nil), &p); err != nil || p != &n {
+		t.Errorf("ToGo of user value got %v, %v", p, err)
+	}
+
+	var a [3]int
+	if err := ToGo(NewArray([]Object{NewInteger(1), NewInteger(2)}), &a); err != nil || a != [3]int{1, 2, 0} {
+		t.Errorf("ToGo to array got %v, %v", a, err)
+	}
+}
+
+func TestToGoError(t *testing.T) {
+	var server testServer
+	var small int8
+	var u uint
+	var pair [1]int
+	var s string
+
+	tests := []struct {
+		value    Object
+		target   interface{}
+		expected string
+	}{
+		{
+			NewInteger(1),
+			server,
+			"target must be a non-nil pointer, but got object.testServer",
+		},
+		{
+			NewHash([]HashPair{{NewString("port"), NewString("80")}}),
+			&server,
+			"STRING can not be converted to Go int at .port",
+		},
+		{
+			NewHash([]HashPair{{NewString("tags"), NewArray([]Object{NewInteger(1)})}}),
+			&server,
+			"INTEGER can not be converted to Go string at .tags[0]",
+		},
+		{
+			NewHash([]HashPair{{NewString("labels"), NewHash([]HashPair{{NewString("x"), NewFloat(1)}})}}),
+			&server,
+			"FLOAT can not be converted to Go int at .labels[x]",
+		},
+		{
+			NewInteger(300),
+			&small,
+			"INTEGER 300 overflows Go int8",
+		},
+		{
+			NewInteger(-1),
+			&u,
+			"INTEGER -1 overflows Go uint",
+		},
+		{
+			NewArray([]Object{NewInteger(1), NewInteger(2)}),
+			&pair,
+			"ARRAY of 2 elements overflows Go [1]int",
+		},
+		{
+			NewUserValue("n", 1, nil),
+			&s,
+			"USERVALUE can not be converted to Go string",
+		},
+	}
+
+	for _, c := range tests {
+		err := ToGo(c.value, c.target)
+		if err == nil {
+			t.Fatalf("ToGo(%s) expect error", c.value.Inspect())
+		}
+
+		if !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("ToGo(%s) expect error %q, got %q", c.value.Inspect(), c.expected, err)
+		}
+	}
+}
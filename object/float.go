@@ -0,0 +1,177 @@
+package object
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/flily/macaque-lang/token"
+)
+
+type FloatObject struct {
+	Value float64
+}
+
+func NewFloat(value float64) Object {
+	o := &FloatObject{
+		Value: value,
+	}
+
+	return o
+}
+
+func (f *FloatObject) Type() ObjectType {
+	return ObjectTypeFloat
+}
+
+func (f *FloatObject) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if math.IsInf(f.Value, 0) || math.IsNaN(f.Value) {
+		return s
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '.', 'e', 'E':
+			return s
+		}
+	}
+
+	// Keep a trailing ".0" so a float never looks like an integer.
+	return s + ".0"
+}
+
+func (f *FloatObject) Hashable() bool {
+	return true
+}
+
+// HashKey returns the value as an int64 when the float has an integral value,
+// so that h[1] and h[1.0] refer to the same item, like 1 == 1.0 does.
+func (f *FloatObject) HashKey() interface{} {
+	if i, ok := f.integral(); ok {
+		return i
+	}
+
+	return f.Value
+}
+
+func (f *FloatObject) integral() (int64, bool) {
+	if f.Value != math.Trunc(f.Value) {
+		return 0, false
+	}
+
+	if f.Value < math.MinInt64 || f.Value >= math.MaxInt64 {
+		return 0, false
+	}
+
+	return int64(f.Value), true
+}
+
+func (f *FloatObject) EqualTo(o Object) bool {
+	switch v := o.(type) {
+	case *FloatObject:
+		return f.Value == v.Value
+
+	case *IntegerObject:
+		return f.Value == v.toFloat().Value
+	}
+
+	return false
+}
+
+func (f *FloatObject) OnPrefix(t token.Token) (Object, bool) {
+	var r Object
+	ok := false
+
+	switch t {
+	case token.Bang:
+		r, ok = NewBoolean(false), true
+
+	case token.Minus:
+		r, ok = NewFloat(-f.Value), true
+	}
+
+	return r, ok
+}
+
+func (f *FloatObject) OnInfix(t token.Token, o Object) (Object, bool) {
+	var r Object
+	ok := false
+
+	switch v := o.(type) {
+	case *FloatObject:
+		r, ok = f.onFloatInfix(t, v)
+
+	case *IntegerObject:
+		r, ok = f.onFloatInfix(t, v.toFloat())
+
+	default:
+		if t == token.EQ || t == token.NE {
+			return doEqualCompare(t, false)
+		}
+	}
+
+	return r, ok
+}
+
+func (f *FloatObject) OnIndex(o Object) (Object, bool) {
+	return nil, false
+}
+
+func (f *FloatObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (f *FloatObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+func (f *FloatObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(f, name)
+}
+
+func (f *FloatObject) onFloatInfix(t token.Token, o *FloatObject) (Object, bool) {
+	var r Object
+	ok := false
+	switch t {
+	case token.Plus:
+		r, ok = NewFloat(f.Value+o.Value), true
+
+	case token.Minus:
+		r, ok = NewFloat(f.Value-o.Value), true
+
+	case token.Asterisk:
+		r, ok = NewFloat(f.Value*o.Value), true
+
+	case token.Slash:
+		r, ok = NewFloat(f.Value/o.Value), true
+
+	case token.Modulo:
+		r, ok = NewFloat(math.Mod(f.Value, o.Value)), true
+
+	case token.EQ:
+		r, ok = NewBoolean(f.Value == o.Value), true
+
+	case token.NE:
+		r, ok = NewBoolean(f.Value != o.Value), true
+
+	case token.LT:
+		r, ok = NewBoolean(f.Value < o.Value), true
+
+	case token.GT:
+		r, ok = NewBoolean(f.Value > o.Value), true
+
+	case token.LE:
+		r, ok = NewBoolean(f.Value <= o.Value), true
+
+	case token.GE:
+		r, ok = NewBoolean(f.Value >= o.Value), true
+
+	case token.AND:
+		r, ok = NewBoolean(f.Value != 0 && o.Value != 0), true
+
+	case token.OR:
+		r, ok = NewBoolean(f.Value != 0 || o.Value != 0), true
+	}
+
+	return r, ok
+}
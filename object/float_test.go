@@ -0,0 +1,173 @@
+package object
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flily/macaque-lang/token"
+)
+
+func TestFloatObject(t *testing.T) {
+	f := NewFloat(3.5)
+
+	if f.Type() != ObjectTypeFloat {
+		t.Errorf("float.Type() is not ObjectTypeFloat")
+	}
+
+	if f.Inspect() != "3.5" {
+		t.Errorf("float.Inspect() wrong, expected %q, got %q",
+			"3.5", f.Inspect())
+	}
+
+	if !f.Hashable() {
+		t.Errorf("float.Hashable() is not true")
+	}
+
+	if f.HashKey() != float64(3.5) {
+		t.Errorf("float.HashKey() is not 3.5, got %v", f.HashKey())
+	}
+}
+
+func TestFloatObjectInspect(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{1, "1.0"},
+		{-2, "-2.0"},
+		{0.25, "0.25"},
+		{1e100, "1e+100"},
+		{math.Inf(1), "+Inf"},
+		{math.NaN(), "NaN"},
+	}
+
+	for _, tt := range tests {
+		got := NewFloat(tt.value).Inspect()
+		if got != tt.expected {
+			t.Errorf("float(%v).Inspect() wrong, expected %q, got %q",
+				tt.value, tt.expected, got)
+		}
+	}
+}
+
+func TestFloatObjectHashKey(t *testing.T) {
+	h := NewHash([]HashPair{
+		{Key: NewInteger(1), Value: NewString("one")},
+		{Key: NewFloat(2.5), Value: NewString("two and a half")},
+	})
+
+	tests := []testObjectEvaluationCase{
+		evalTest("HASH[FLOAT(1.0)]").
+			call(h.OnIndex(NewFloat(1.0))).
+			expect(NewString("one"), true),
+		evalTest("HASH[FLOAT(2.5)]").
+			call(h.OnIndex(NewFloat(2.5))).
+			expect(NewString("two and a half"), true),
+		evalTest("HASH[INTEGER(2)]").
+			call(h.OnIndex(NewInteger(2))).
+			expect(NewNull(), true),
+	}
+
+	testObjectEvaluation(t, tests)
+}
+
+func TestFloatObjectPrefixEvaluation(t *testing.T) {
+	f := NewFloat(1.5)
+
+	tests := []testObjectEvaluationCase{
+		evalTest("-FLOAT").
+			call(f.OnPrefix(token.Minus)).
+			expect(NewFloat(-1.5), true),
+		evalTest("!FLOAT").
+			call(f.OnPrefix(token.Bang)).
+			expect(NewBoolean(false), true),
+		evalTest("~FLOAT").
+			call(f.OnPrefix(token.BITNOT)).
+			expect(nil, false),
+	}
+
+	testObjectEvaluation(t, tests)
+}
+
+func TestFloatObjectInfixOnFloatEvaluation(t *testing.T) {
+	f := NewFloat(7.5)
+	g := NewFloat(2.5)
+
+	tests := []testObjectEvaluationCase{
+		evalTest("FLOAT(7.5) == FLOAT(2.5)").
+			call(f.OnInfix(token.EQ, g)).
+			expect(NewBoolean(false), true),
+		evalTest("FLOAT(7.5) != FLOAT(2.5)").
+			call(f.OnInfix(token.NE, g)).
+			expect(NewBoolean(true), true),
+		evalTest("FLOAT(7.5) == STRING(7.5)").
+			call(f.OnInfix(token.EQ, NewString("7.5"))).
+			expect(NewBoolean(false), true),
+		evalTest("FLOAT(7.5) + FLOAT(2.5)").
+			call(f.OnInfix(token.Plus, g)).
+			expect(NewFloat(10), true),
+		evalTest("FLOAT(7.5) - FLOAT(2.5)").
+			call(f.OnInfix(token.Minus, g)).
+			expect(NewFloat(5), true),
+		evalTest("FLOAT(7.5) * FLOAT(2.5)").
+			call(f.OnInfix(token.Asterisk, g)).
+			expect(NewFloat(18.75), true),
+		evalTest("FLOAT(7.5) / FLOAT(2.5)").
+			call(f.OnInfix(token.Slash, g)).
+			expect(NewFloat(3), true),
+		evalTest("FLOAT(7.5) % FLOAT(2.5)").
+			call(f.OnInfix(token.Modulo, g)).
+			expect(NewFloat(0), true),
+		evalTest("FLOAT(7.5) < FLOAT(2.5)").
+			call(f.OnInfix(token.LT, g)).
+			expect(NewBoolean(false), true),
+		evalTest("FLOAT(7.5) > FLOAT(2.5)").
+			call(f.OnInfix(token.GT, g)).
+			expect(NewBoolean(true), true),
+		evalTest("FLOAT(7.5) <= FLOAT(2.5)").
+			call(f.OnInfix(token.LE, g)).
+			expect(NewBoolean(false), true),
+		evalTest("FLOAT(7.5) >= FLOAT(2.5)").
+			call(f.OnInfix(token.GE, g)).
+			expect(NewBoolean(true), true),
+		evalTest("FLOAT(7.5) & FLOAT(2.5)").
+			call(f.OnInfix(token.BITAND, g)).
+			expect(nil, false),
+		evalTest("FLOAT(7.5) + STRING(2.5)").
+			call(f.OnInfix(token.Plus, NewString("2.5"))).
+			expect(nil, false),
+	}
+
+	testObjectEvaluation(t, tests)
+}
+
+func TestIntegerFloatPromotion(t *testing.T) {
+	i := NewInteger(3)
+	f := NewFloat(1.5)
+
+	tests := []testObjectEvaluationCase{
+		evalTest("INTEGER(3) + FLOAT(1.5)").
+			call(i.OnInfix(token.Plus, f)).
+			expect(NewFloat(4.5), true),
+		evalTest("FLOAT(1.5) + INTEGER(3)").
+			call(f.OnInfix(token.Plus, i)).
+			expect(NewFloat(4.5), true),
+		evalTest("INTEGER(3) / FLOAT(1.5)").
+			call(i.OnInfix(token.Slash, f)).
+			expect(NewFloat(2), true),
+		evalTest("INTEGER(3) > FLOAT(1.5)").
+			call(i.OnInfix(token.GT, f)).
+			expect(NewBoolean(true), true),
+		evalTest("INTEGER(3) == FLOAT(3.0)").
+			call(i.OnInfix(token.EQ, NewFloat(3))).
+			expect(NewBoolean(true), true),
+		evalTest("FLOAT(3.0) != INTEGER(3)").
+			call(NewFloat(3).OnInfix(token.NE, i)).
+			expect(NewBoolean(false), true),
+		evalTest("INTEGER(3) & FLOAT(1.5)").
+			call(i.OnInfix(token.BITAND, f)).
+			expect(nil, false),
+	}
+
+	testObjectEvaluation(t, tests)
+}
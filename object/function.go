@@ -10,6 +10,7 @@ type FunctionObject struct {
 	Index     uint64
 	FrameSize int
 	Arguments int
+	Variadic  bool
 	IP        uint64
 	Bounds    []Object
 }
@@ -88,3 +89,15 @@ func (f *FunctionObject) OnInfix(t token.Token, o Object) (Object, bool) {
 func (f *FunctionObject) OnIndex(o Object) (Object, bool) {
 	return nil, false
 }
+
+func (f *FunctionObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (f *FunctionObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+func (f *FunctionObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(f, name)
+}
@@ -105,3 +105,45 @@ func (h *HashObject) OnIndex(o Object) (Object, bool) {
 
 	return objectNull, true
 }
+
+// OnAssignIndex sets value of key index, new keys are added to the end.
+func (h *HashObject) OnAssignIndex(index Object, value Object) bool {
+	if !index.Hashable() {
+		return false
+	}
+
+	key := index.HashKey()
+	pair := HashPair{
+		Key:   index,
+		Value: value,
+	}
+
+	if _, ok := h.Map[key]; ok {
+		for i, e := range h.Elements {
+			if e.Key.HashKey() == key {
+				h.Elements[i] = pair
+				break
+			}
+		}
+
+	} else {
+		h.Elements = append(h.Elements, pair)
+	}
+
+	h.Map[key] = pair
+	return true
+}
+
+func (h *HashObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+// OnMethod finds member name of the hash first, then the method table, so
+// functions in a hash are called like methods of an object.
+func (h *HashObject) OnMethod(name string) (Object, bool) {
+	if e, ok := h.Map[name]; ok {
+		return e.Value, true
+	}
+
+	return lookupMethod(h, name)
+}
@@ -71,6 +71,13 @@ func TestHashObjectEvaluation(t *testing.T) {
 				{NewString("three"), NewInteger(33)},
 			}))).
 			expect(NewBoolean(false), true),
+		evalTest("HASH{one: 1, two: 2, three: 3} == HASH{one: 1.0, two: 2, three: 3.0}").
+			call(h.OnInfix(token.EQ, NewHash([]HashPair{
+				{NewString("one"), NewFloat(1.0)},
+				{NewString("two"), NewInteger(2)},
+				{NewString("three"), NewFloat(3.0)},
+			}))).
+			expect(NewBoolean(true), true),
 		evalTest("HASH{one: 1, two: 2, three: 3} == 42").
 			call(h.OnInfix(token.EQ, NewInteger(42))).
 			expect(NewBoolean(false), true),
@@ -115,3 +122,32 @@ func TestHashOnIndexEvaluation(t *testing.T) {
 
 	testObjectEvaluation(t, tests)
 }
+
+func TestHashOnAssignIndex(t *testing.T) {
+	h := NewHash([]HashPair{
+		{NewString("one"), NewInteger(1)},
+		{NewString("two"), NewInteger(2)},
+	})
+
+	if !h.OnAssignIndex(NewString("one"), NewInteger(11)) {
+		t.Errorf("assign to existing key failed")
+	}
+
+	if !h.OnAssignIndex(NewString("three"), NewInteger(3)) {
+		t.Errorf("assign to new key failed")
+	}
+
+	if h.OnAssignIndex(NewNull(), NewInteger(0)) {
+		t.Errorf("assign to unhashable key succeeded")
+	}
+
+	expected := `{one: 11, two: 2, three: 3}`
+	if h.Inspect() != expected {
+		t.Errorf("wrong hash after assignment, expected %s, got %s", expected, h.Inspect())
+	}
+
+	v, _ := h.OnIndex(NewString("three"))
+	if !v.EqualTo(NewInteger(3)) {
+		t.Errorf("wrong value of new key, got %s", v.Inspect())
+	}
+}
@@ -38,6 +38,9 @@ func (i *IntegerObject) EqualTo(o Object) bool {
 	switch v := o.(type) {
 	case *IntegerObject:
 		return i.Value == v.Value
+
+	case *FloatObject:
+		return i.toFloat().Value == v.Value
 	}
 
 	return false
@@ -65,6 +68,11 @@ func (i *IntegerObject) OnInfix(t token.Token, o Object) (Object, bool) {
 	var r Object
 	ok := false
 
+	// Integer is promoted to float when the other operand is a float.
+	if v, isFloat := o.(*FloatObject); isFloat {
+		return i.toFloat().onFloatInfix(t, v)
+	}
+
 	if t == token.EQ || t == token.NE {
 		return doEqualCompare(t, i.EqualTo(o))
 	}
@@ -78,10 +86,26 @@ func (i *IntegerObject) OnInfix(t token.Token, o Object) (Object, bool) {
 	return r, ok
 }
 
+func (i *IntegerObject) toFloat() *FloatObject {
+	return &FloatObject{Value: float64(i.Value)}
+}
+
 func (i *IntegerObject) OnIndex(o Object) (Object, bool) {
 	return nil, false
 }
 
+func (i *IntegerObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (i *IntegerObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+func (i *IntegerObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(i, name)
+}
+
 func (i *IntegerObject) onIntegerInfix(t token.Token, o *IntegerObject) (Object, bool) {
 	var r Object
 	ok := false
@@ -96,11 +120,15 @@ func (i *IntegerObject) onIntegerInfix(t token.Token, o *IntegerObject) (Object,
 		r, ok = NewInteger(i.Value*o.Value), true
 
 	case token.Slash:
-		// NOTES: will crash if o.Value == 0
-		r, ok = NewInteger(i.Value/o.Value), true
+		// Division by zero is not accepted, instead of panic.
+		if o.Value != 0 {
+			r, ok = NewInteger(i.Value/o.Value), true
+		}
 
 	case token.Modulo:
-		r, ok = NewInteger(i.Value%o.Value), true
+		if o.Value != 0 {
+			r, ok = NewInteger(i.Value%o.Value), true
+		}
 
 	case token.LT:
 		r, ok = NewBoolean(i.Value < o.Value), true
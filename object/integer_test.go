@@ -68,6 +68,12 @@ func TestIntegerObjectInfixOnIntegerEvaluation(t *testing.T) {
 		evalTest("INTEGER(42) + INTEGER(2)").
 			call(i.OnInfix(token.Plus, j)).
 			expect(NewInteger(44), true),
+		evalTest("INTEGER(42) / INTEGER(0)").
+			call(i.OnInfix(token.Slash, NewInteger(0))).
+			expect(nil, false),
+		evalTest("INTEGER(42) % INTEGER(0)").
+			call(i.OnInfix(token.Modulo, NewInteger(0))).
+			expect(nil, false),
 		evalTest("INTEGER(42) - INTEGER(2)").
 			call(i.OnInfix(token.Minus, j)).
 			expect(NewInteger(40), true),
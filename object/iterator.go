@@ -0,0 +1,113 @@
+package object
+
+import (
+	"github.com/flily/macaque-lang/token"
+)
+
+// IteratorObject walks elements of an iterable object for for-in loops.
+// Iterators are never visible to user code.
+type IteratorObject struct {
+	Base  Object
+	index int64
+}
+
+// NewIterator makes an iterator of o, returns false if o is not iterable.
+//   - Array: elements, the array is read at every step.
+//   - Hash: keys, in the order of insertion.
+//   - String: each byte as a string of one byte.
+//   - Integer n: integers from 0 to n-1.
+func NewIterator(o Object) (*IteratorObject, bool) {
+	switch o.(type) {
+	case *ArrayObject, *HashObject, *StringObject, *IntegerObject:
+		i := &IteratorObject{
+			Base: o,
+		}
+		return i, true
+	}
+
+	return nil, false
+}
+
+// Next returns the next element, or false if all elements are walked.
+func (i *IteratorObject) Next() (Object, bool) {
+	var r Object
+	n := i.index
+
+	switch v := i.Base.(type) {
+	case *ArrayObject:
+		if n < int64(len(v.Elements)) {
+			r = v.Elements[n]
+		}
+
+	case *HashObject:
+		if n < int64(len(v.Elements)) {
+			r = v.Elements[n].Key
+		}
+
+	case *StringObject:
+		if n < int64(len(v.Value)) {
+			r = NewString(v.Value[n : n+1])
+		}
+
+	case *IntegerObject:
+		if n < v.Value {
+			r = NewInteger(n)
+		}
+	}
+
+	if r == nil {
+		return nil, false
+	}
+
+	i.index = n + 1
+	return r, true
+}
+
+func (i *IteratorObject) Type() ObjectType {
+	return ObjectTypeIterator
+}
+
+func (i *IteratorObject) Inspect() string {
+	return "iterator(" + i.Base.Inspect() + ")"
+}
+
+func (i *IteratorObject) Hashable() bool {
+	return false
+}
+
+func (i *IteratorObject) HashKey() interface{} {
+	return nil
+}
+
+func (i *IteratorObject) EqualTo(o Object) bool {
+	switch v := o.(type) {
+	case *IteratorObject:
+		return i == v
+	}
+
+	return false
+}
+
+func (i *IteratorObject) OnPrefix(t token.Token) (Object, bool) {
+	return nil, false
+}
+
+func (i *IteratorObject) OnInfix(t token.Token, o Object) (Object, bool) {
+	return nil, false
+}
+
+func (i *IteratorObject) OnIndex(o Object) (Object, bool) {
+	return nil, false
+}
+
+func (i *IteratorObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (i *IteratorObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+func (i *IteratorObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(i, name)
+}
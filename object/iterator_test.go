@@ -0,0 +1,97 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestIteratorObject(t *testing.T) {
+	tests := []struct {
+		base     Object
+		expected []Object
+	}{
+		{
+			NewArray([]Object{NewInteger(1), NewString("a")}),
+			[]Object{NewInteger(1), NewString("a")},
+		},
+		{
+			NewHash([]HashPair{
+				{NewString("b"), NewInteger(1)},
+				{NewString("a"), NewInteger(2)},
+			}),
+			[]Object{NewString("b"), NewString("a")},
+		},
+		{
+			NewString("abc"),
+			[]Object{NewString("a"), NewString("b"), NewString("c")},
+		},
+		{
+			NewString("héllo"),
+			[]Object{
+				NewString("h"), NewString("\xc3"), NewString("\xa9"),
+				NewString("l"), NewString("l"), NewString("o"),
+			},
+		},
+		{
+			NewInteger(3),
+			[]Object{NewInteger(0), NewInteger(1), NewInteger(2)},
+		},
+		{
+			NewInteger(-1),
+			[]Object{},
+		},
+	}
+
+	for _, tt := range tests {
+		i, ok := NewIterator(tt.base)
+		if !ok {
+			t.Fatalf("%s is not iterable", tt.base.Inspect())
+		}
+
+		for j, expected := range tt.expected {
+			o, ok := i.Next()
+			if !ok {
+				t.Fatalf("iterator of %s stops at %d", tt.base.Inspect(), j)
+			}
+
+			if !o.EqualTo(expected) {
+				t.Errorf("iterator of %s [%d] expected %s, got %s",
+					tt.base.Inspect(), j, expected.Inspect(), o.Inspect())
+			}
+		}
+
+		if o, ok := i.Next(); ok {
+			t.Errorf("iterator of %s does not stop, got %s",
+				tt.base.Inspect(), o.Inspect())
+		}
+	}
+}
+
+func TestIteratorObjectOnArrayChanged(t *testing.T) {
+	array := &ArrayObject{
+		Elements: []Object{NewInteger(1)},
+	}
+
+	i, _ := NewIterator(array)
+	if o, ok := i.Next(); !ok || !o.EqualTo(NewInteger(1)) {
+		t.Fatalf("first element of iterator is wrong")
+	}
+
+	array.Elements = append(array.Elements, NewInteger(2))
+	if o, ok := i.Next(); !ok || !o.EqualTo(NewInteger(2)) {
+		t.Fatalf("appended element is not walked")
+	}
+}
+
+func TestIteratorObjectNotIterable(t *testing.T) {
+	tests := []Object{
+		NewNull(),
+		NewBoolean(true),
+		NewFloat(1.5),
+	}
+
+	for _, o := range tests {
+		if _, ok := NewIterator(o); ok {
+			t.Errorf("%s is iterable", o.Inspect())
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/flily/macaque-lang/token"
+)
+
+// Caller calls a function from native code, it is implemented by VM. Function
+// can be a script function or a native method.
+type Caller interface {
+	CallFunction(fn Object, args ...Object) ([]Object, error)
+}
+
+// Method is a native method of a type, called with the receiver as self.
+type Method func(c Caller, self Object, args []Object) ([]Object, error)
+
+// MethodObject is a native method found by OnMethod. It is called with the
+// receiver as the first argument, like a method call by `::`.
+type MethodObject struct {
+	Name   string
+	Method Method
+}
+
+var methodTables = make(map[ObjectType]map[string]*MethodObject)
+
+// RegisterMethod adds method name to the method table of type t, an existing
+// method with the same name is replaced.
+func RegisterMethod(t ObjectType, name string, m Method) {
+	table, ok := methodTables[t]
+	if !ok {
+		table = make(map[string]*MethodObject)
+		methodTables[t] = table
+	}
+
+	table[name] = &MethodObject{
+		Name:   name,
+		Method: m,
+	}
+}
+
+// LookupMethod finds method name in the method table of type t.
+func LookupMethod(t ObjectType, name string) (*MethodObject, bool) {
+	m, ok := methodTables[t][name]
+	return m, ok
+}
+
+// lookupMethod is the default OnMethod of objects, which only looks up the
+// method table of their type.
+func lookupMethod(o Object, name string) (Object, bool) {
+	m, ok := LookupMethod(o.Type(), name)
+	if !ok {
+		return nil, false
+	}
+
+	return m, true
+}
+
+// Call calls method with args, args[0] is the receiver.
+func (m *MethodObject) Call(c Caller, args []Object) ([]Object, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("method %s requires a receiver", m.Name)
+	}
+
+	return m.Method(c, args[0], args[1:])
+}
+
+func (m *MethodObject) Type() ObjectType {
+	return ObjectTypeFunction
+}
+
+func (m *MethodObject) Inspect() string {
+	return fmt.Sprintf("method[%s]", m.Name)
+}
+
+func (m *MethodObject) Hashable() bool {
+	return false
+}
+
+func (m *MethodObject) HashKey() interface{} {
+	return nil
+}
+
+func (m *MethodObject) EqualTo(o Object) bool {
+	switch v := o.(type) {
+	case *MethodObject:
+		return m == v
+	}
+
+	return false
+}
+
+func (m *MethodObject) OnPrefix(t token.Token) (Object, bool) {
+	var r Object
+	ok := false
+	switch t {
+	case token.Bang:
+		r, ok = objectFalse, true
+	}
+
+	return r, ok
+}
+
+func (m *MethodObject) OnInfix(t token.Token, o Object) (Object, bool) {
+	if t == token.EQ || t == token.NE {
+		return doEqualCompare(t, m.EqualTo(o))
+	}
+
+	return nil, false
+}
+
+func (m *MethodObject) OnIndex(o Object) (Object, bool) {
+	return nil, false
+}
+
+func (m *MethodObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (m *MethodObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+func (m *MethodObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(m, name)
+}
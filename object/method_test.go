@@ -0,0 +1,163 @@
+package object
+
+import (
+	"fmt"
+	"testing"
+)
+
+// testCaller calls native methods, and Go functions wrapped in test methods.
+type testCaller struct{}
+
+func (c testCaller) CallFunction(fn Object, args ...Object) ([]Object, error) {
+	m, ok := fn.(*MethodObject)
+	if !ok {
+		return nil, fmt.Errorf("%s is not callable", fn.Type())
+	}
+
+	return m.Call(c, args)
+}
+
+func testMethod(f func(args []Object) Object) *MethodObject {
+	return &MethodObject{
+		Name: "test",
+		Method: func(c Caller, self Object, args []Object) ([]Object, error) {
+			return []Object{f(append([]Object{self}, args...))}, nil
+		},
+	}
+}
+
+func callMethod(self Object, name string, args ...Object) (Object, error) {
+	m, ok := self.OnMethod(name)
+	if !ok {
+		return nil, fmt.Errorf("%s has no method %s", self.Type(), name)
+	}
+
+	result, err := testCaller{}.CallFunction(m, append([]Object{self}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result[0], nil
+}
+
+func TestRegisterMethod(t *testing.T) {
+	RegisterMethod(ObjectTypeBoolean, "test_not", func(c Caller, self Object, args []Object) ([]Object, error) {
+		return []Object{NewBoolean(!self.(*BooleanObject).Value)}, nil
+	})
+	defer delete(methodTables[ObjectTypeBoolean], "test_not")
+
+	m, ok := LookupMethod(ObjectTypeBoolean, "test_not")
+	if !ok {
+		t.Fatalf("method test_not not found")
+	}
+
+	if m.Type() != ObjectTypeFunction || m.Inspect() != "method[test_not]" {
+		t.Errorf("wrong method object: %s %s", m.Type(), m.Inspect())
+	}
+
+	got, err := callMethod(NewBoolean(true), "test_not")
+	if err != nil || !got.EqualTo(NewBoolean(false)) {
+		t.Errorf("true::test_not() got %v, %v", got, err)
+	}
+
+	if _, ok := NewInteger(1).OnMethod("test_not"); ok {
+		t.Errorf("method of BOOLEAN is found on INTEGER")
+	}
+
+	if _, err := m.Call(testCaller{}, nil); err == nil {
+		t.Errorf("method is called without receiver")
+	}
+}
+
+func TestNativeMethods(t *testing.T) {
+	double := testMethod(func(args []Object) Object {
+		return NewInteger(args[0].(*IntegerObject).Value * 2)
+	})
+
+	odd := testMethod(func(args []Object) Object {
+		return NewBoolean(args[0].(*IntegerObject).Value%2 == 1)
+	})
+
+	add := testMethod(func(args []Object) Object {
+		return NewInteger(args[0].(*IntegerObject).Value + args[1].(*IntegerObject).Value)
+	})
+
+	array := NewArray([]Object{NewInteger(1), NewInteger(2), NewInteger(3)})
+	hash := NewHash([]HashPair{
+		{Key: NewString("a"), Value: NewInteger(1)},
+	})
+
+	tests := []struct {
+		self     Object
+		name     string
+		args     []Object
+		expected Object
+	}{
+		{NewInteger(-3), "abs", nil, NewInteger(3)},
+		{NewInteger(3), "to_float", nil, NewFloat(3)},
+		{NewInteger(3), "to_string", nil, NewString("3")},
+		{NewFloat(-1.5), "abs", nil, NewFloat(1.5)},
+		{NewFloat(1.5), "floor", nil, NewInteger(1)},
+		{NewFloat(1.5), "ceil", nil, NewInteger(2)},
+		{NewFloat(-1.5), "to_int", nil, NewInteger(-1)},
+		{NewString("aBc"), "upper", nil, NewString("ABC")},
+		{NewString("aBc"), "lower", nil, NewString("abc")},
+		{NewString(" x "), "trim", nil, NewString("x")},
+		{NewString("abc"), "length", nil, NewInteger(3)},
+		{NewString("abc"), "contains", []Object{NewString("bc")}, NewBoolean(true)},
+		{NewString(" 42"), "to_int", nil, NewInteger(42)},
+		{NewString("x"), "to_int", nil, NewNull()},
+		{NewString("a,b"), "split", []Object{NewString(",")},
+			NewArray([]Object{NewString("a"), NewString("b")})},
+		{array, "length", nil, NewInteger(3)},
+		{array, "map", []Object{double},
+			NewArray([]Object{NewInteger(2), NewInteger(4), NewInteger(6)})},
+		{array, "filter", []Object{odd},
+			NewArray([]Object{NewInteger(1), NewInteger(3)})},
+		{array, "reduce", []Object{add, NewInteger(10)}, NewInteger(16)},
+		{array, "join", []Object{NewString("-")}, NewString("1-2-3")},
+		{array, "each", []Object{double}, array},
+		{NewInteger(3), "times", []Object{double}, NewInteger(3)},
+		{hash, "length", nil, NewInteger(1)},
+		{hash, "keys", nil, NewArray([]Object{NewString("a")})},
+		{hash, "values", nil, NewArray([]Object{NewInteger(1)})},
+		{hash, "has", []Object{NewString("b")}, NewBoolean(false)},
+		{hash, "has", []Object{array}, NewBoolean(false)},
+	}
+
+	for _, c := range tests {
+		got, err := callMethod(c.self, c.name, c.args...)
+		if err != nil {
+			t.Errorf("%s::%s() got error: %s", c.self.Inspect(), c.name, err)
+			continue
+		}
+
+		if !got.EqualTo(c.expected) {
+			t.Errorf("%s::%s() got %s, expected %s",
+				c.self.Inspect(), c.name, got.Inspect(), c.expected.Inspect())
+		}
+	}
+}
+
+func TestNativeMethodsError(t *testing.T) {
+	tests := []struct {
+		self     Object
+		name     string
+		args     []Object
+		expected string
+	}{
+		{NewInteger(1), "abs", []Object{NewInteger(1)}, "abs requires 0 arguments, but got 1"},
+		{NewString("a"), "split", []Object{NewInteger(1)},
+			"split requires a STRING separator, but got INTEGER"},
+		{NewArray(nil), "join", []Object{NewNull()},
+			"join requires a STRING separator, but got NULL"},
+		{NewInteger(1), "upper", nil, "INTEGER has no method upper"},
+	}
+
+	for _, c := range tests {
+		_, err := callMethod(c.self, c.name, c.args...)
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("%s::%s() got error %v, expected %s", c.self.Inspect(), c.name, err, c.expected)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Native methods of basic types, called by `::` like `5::times(f)`.
+func init() {
+	RegisterMethod(ObjectTypeInteger, "times", integerTimes)
+	RegisterMethod(ObjectTypeInteger, "abs", integerAbs)
+	RegisterMethod(ObjectTypeInteger, "to_float", integerToFloat)
+	RegisterMethod(ObjectTypeInteger, "to_string", inspectString)
+
+	RegisterMethod(ObjectTypeFloat, "abs", floatAbs)
+	RegisterMethod(ObjectTypeFloat, "floor", floatFloor)
+	RegisterMethod(ObjectTypeFloat, "ceil", floatCeil)
+	RegisterMethod(ObjectTypeFloat, "to_int", floatToInt)
+	RegisterMethod(ObjectTypeFloat, "to_string", inspectString)
+
+	RegisterMethod(ObjectTypeString, "length", stringLength)
+	RegisterMethod(ObjectTypeString, "upper", stringUpper)
+	RegisterMethod(ObjectTypeString, "lower", stringLower)
+	RegisterMethod(ObjectTypeString, "trim", stringTrim)
+	RegisterMethod(ObjectTypeString, "split", stringSplit)
+	RegisterMethod(ObjectTypeString, "contains", stringContains)
+	RegisterMethod(ObjectTypeString, "to_int", stringToInt)
+
+	RegisterMethod(ObjectTypeArray, "length", arrayLength)
+	RegisterMethod(ObjectTypeArray, "each", arrayEach)
+	RegisterMethod(ObjectTypeArray, "map", arrayMap)
+	RegisterMethod(ObjectTypeArray, "filter", arrayFilter)
+	RegisterMethod(ObjectTypeArray, "reduce", arrayReduce)
+	RegisterMethod(ObjectTypeArray, "join", arrayJoin)
+
+	RegisterMethod(ObjectTypeHash, "length", hashLength)
+	RegisterMethod(ObjectTypeHash, "keys", hashKeys)
+	RegisterMethod(ObjectTypeHash, "values", hashValues)
+	RegisterMethod(ObjectTypeHash, "has", hashHas)
+}
+
+// IsTrue checks whether o is true as a condition, only null and false are not.
+func IsTrue(o Object) bool {
+	switch v := o.(type) {
+	case *NullObject:
+		return false
+
+	case *BooleanObject:
+		return v.Value
+	}
+
+	return true
+}
+
+// Values returns objects as return values of a native function.
+func Values(o ...Object) []Object {
+	return o
+}
+
+// CheckArguments returns an error if args of function name are not exactly n.
+func CheckArguments(name string, args []Object, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("%s requires %d arguments, but got %d", name, n, len(args))
+	}
+
+	return nil
+}
+
+// CallOne calls fn and returns its first return value, or null if nothing is
+// returned.
+func CallOne(c Caller, fn Object, args ...Object) (Object, error) {
+	result, err := c.CallFunction(fn, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return objectNull, nil
+	}
+
+	return result[0], nil
+}
+
+func inspectString(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("to_string", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewString(self.Inspect())), nil
+}
+
+func integerTimes(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("times", args, 1); err != nil {
+		return nil, err
+	}
+
+	n := self.(*IntegerObject).Value
+	for i := int64(0); i < n; i++ {
+		if _, err := c.CallFunction(args[0], NewInteger(i)); err != nil {
+			return nil, err
+		}
+	}
+
+	return Values(self), nil
+}
+
+func integerAbs(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("abs", args, 0); err != nil {
+		return nil, err
+	}
+
+	n := self.(*IntegerObject).Value
+	if n < 0 {
+		n = -n
+	}
+
+	return Values(NewInteger(n)), nil
+}
+
+func integerToFloat(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("to_float", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewFloat(float64(self.(*IntegerObject).Value))), nil
+}
+
+func floatAbs(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("abs", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewFloat(math.Abs(self.(*FloatObject).Value))), nil
+}
+
+func floatFloor(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("floor", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewInteger(int64(math.Floor(self.(*FloatObject).Value)))), nil
+}
+
+func floatCeil(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("ceil", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewInteger(int64(math.Ceil(self.(*FloatObject).Value)))), nil
+}
+
+func floatToInt(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("to_int", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewInteger(int64(self.(*FloatObject).Value))), nil
+}
+
+func stringLength(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("length", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewInteger(int64(len(self.(*StringObject).Value)))), nil
+}
+
+func stringUpper(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("upper", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewString(strings.ToUpper(self.(*StringObject).Value))), nil
+}
+
+func stringLower(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("lower", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewString(strings.ToLower(self.(*StringObject).Value))), nil
+}
+
+func stringTrim(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("trim", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewString(strings.TrimSpace(self.(*StringObject).Value))), nil
+}
+
+func stringSplit(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("split", args, 1); err != nil {
+		return nil, err
+	}
+
+	sep, ok := args[0].(*StringObject)
+	if !ok {
+		return nil, fmt.Errorf("split requires a STRING separator, but got %s", args[0].Type())
+	}
+
+	parts := strings.Split(self.(*StringObject).Value, sep.Value)
+	elements := make([]Object, len(parts))
+	for i, part := range parts {
+		elements[i] = NewString(part)
+	}
+
+	return Values(NewArray(elements)), nil
+}
+
+func stringContains(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("contains", args, 1); err != nil {
+		return nil, err
+	}
+
+	sub, ok := args[0].(*StringObject)
+	if !ok {
+		return nil, fmt.Errorf("contains requires a STRING, but got %s", args[0].Type())
+	}
+
+	return Values(NewBoolean(strings.Contains(self.(*StringObject).Value, sub.Value))), nil
+}
+
+func stringToInt(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("to_int", args, 0); err != nil {
+		return nil, err
+	}
+
+	s := self.(*StringObject).Value
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return Values(objectNull), nil
+	}
+
+	return Values(NewInteger(n)), nil
+}
+
+func arrayLength(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("length", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewInteger(int64(len(self.(*ArrayObject).Elements)))), nil
+}
+
+func arrayEach(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("each", args, 1); err != nil {
+		return nil, err
+	}
+
+	for i, e := range self.(*ArrayObject).Elements {
+		if _, err := c.CallFunction(args[0], e, NewInteger(int64(i))); err != nil {
+			return nil, err
+		}
+	}
+
+	return Values(self), nil
+}
+
+func arrayMap(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("map", args, 1); err != nil {
+		return nil, err
+	}
+
+	elements := self.(*ArrayObject).Elements
+	result := make([]Object, len(elements))
+	for i, e := range elements {
+		v, err := CallOne(c, args[0], e, NewInteger(int64(i)))
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = v
+	}
+
+	return Values(NewArray(result)), nil
+}
+
+func arrayFilter(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("filter", args, 1); err != nil {
+		return nil, err
+	}
+
+	result := make([]Object, 0)
+	for i, e := range self.(*ArrayObject).Elements {
+		v, err := CallOne(c, args[0], e, NewInteger(int64(i)))
+		if err != nil {
+			return nil, err
+		}
+
+		if IsTrue(v) {
+			result = append(result, e)
+		}
+	}
+
+	return Values(NewArray(result)), nil
+}
+
+func arrayReduce(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("reduce", args, 2); err != nil {
+		return nil, err
+	}
+
+	acc := args[1]
+	for _, e := range self.(*ArrayObject).Elements {
+		v, err := CallOne(c, args[0], acc, e)
+		if err != nil {
+			return nil, err
+		}
+
+		acc = v
+	}
+
+	return Values(acc), nil
+}
+
+func arrayJoin(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("join", args, 1); err != nil {
+		return nil, err
+	}
+
+	sep, ok := args[0].(*StringObject)
+	if !ok {
+		return nil, fmt.Errorf("join requires a STRING separator, but got %s", args[0].Type())
+	}
+
+	elements := self.(*ArrayObject).Elements
+	parts := make([]string, len(elements))
+	for i, e := range elements {
+		parts[i] = e.Inspect()
+	}
+
+	return Values(NewString(strings.Join(parts, sep.Value))), nil
+}
+
+func hashLength(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("length", args, 0); err != nil {
+		return nil, err
+	}
+
+	return Values(NewInteger(int64(len(self.(*HashObject).Map)))), nil
+}
+
+func hashKeys(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("keys", args, 0); err != nil {
+		return nil, err
+	}
+
+	elements := self.(*HashObject).Elements
+	keys := make([]Object, len(elements))
+	for i, e := range elements {
+		keys[i] = e.Key
+	}
+
+	return Values(NewArray(keys)), nil
+}
+
+func hashValues(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("values", args, 0); err != nil {
+		return nil, err
+	}
+
+	elements := self.(*HashObject).Elements
+	result := make([]Object, len(elements))
+	for i, e := range elements {
+		result[i] = e.Value
+	}
+
+	return Values(NewArray(result)), nil
+}
+
+func hashHas(c Caller, self Object, args []Object) ([]Object, error) {
+	if err := CheckArguments("has", args, 1); err != nil {
+		return nil, err
+	}
+
+	key := args[0]
+	if !key.Hashable() {
+		return Values(objectFalse), nil
+	}
+
+	_, ok := self.(*HashObject).Map[key.HashKey()]
+	return Values(NewBoolean(ok)), nil
+}
@@ -16,6 +16,9 @@ const (
 	ObjectTypeArray      ObjectType = 6
 	ObjectTypeHash       ObjectType = 7
 	ObjectTypeFunction   ObjectType = 8
+	ObjectTypeCell       ObjectType = 9
+	ObjectTypeIterator   ObjectType = 10
+	ObjectTypeUserValue  ObjectType = 11
 	ObjectTypeSystemFlag ObjectType = 64
 )
 
@@ -29,6 +32,9 @@ var objectTypeName = map[ObjectType]string{
 	ObjectTypeArray:      "ARRAY",
 	ObjectTypeHash:       "HASH",
 	ObjectTypeFunction:   "FUNCTION",
+	ObjectTypeCell:       "CELL",
+	ObjectTypeIterator:   "ITERATOR",
+	ObjectTypeUserValue:  "USERVALUE",
 	ObjectTypeSystemFlag: "SYSTEM",
 }
 
@@ -45,6 +51,57 @@ type Object interface {
 	OnPrefix(token.Token) (Object, bool)
 	OnInfix(token.Token, Object) (Object, bool)
 	OnIndex(Object) (Object, bool)
+	OnAssignIndex(Object, Object) bool
+	OnSlice(Object, Object) (Object, bool)
+	OnMethod(string) (Object, bool)
+}
+
+// sliceBounds converts bounds of slice to range of a sequence with length n.
+// Null bound is omitted, negative bound is counted from the end, and bounds are
+// clamped in the sequence.
+func sliceBounds(low Object, high Object, n int) (int, int, bool) {
+	l, ok := sliceBound(low, 0, n)
+	if !ok {
+		return 0, 0, false
+	}
+
+	h, ok := sliceBound(high, n, n)
+	if !ok {
+		return 0, 0, false
+	}
+
+	if l > h {
+		l = h
+	}
+
+	return l, h, true
+}
+
+func sliceBound(o Object, omitted int, n int) (int, bool) {
+	var i int64
+	switch v := o.(type) {
+	case *NullObject:
+		return omitted, true
+
+	case *IntegerObject:
+		i = v.Value
+
+	default:
+		return 0, false
+	}
+
+	if i < 0 {
+		i += int64(n)
+	}
+
+	switch {
+	case i < 0:
+		i = 0
+	case i > int64(n):
+		i = int64(n)
+	}
+
+	return int(i), true
 }
 
 type (
@@ -114,6 +171,18 @@ func (n *NullObject) OnIndex(o Object) (Object, bool) {
 	return nil, false
 }
 
+func (n *NullObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (n *NullObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+func (n *NullObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(n, name)
+}
+
 type BooleanObject struct {
 	Value bool
 }
@@ -178,6 +247,18 @@ func (b *BooleanObject) OnIndex(o Object) (Object, bool) {
 	return nil, false
 }
 
+func (b *BooleanObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (b *BooleanObject) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+func (b *BooleanObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(b, name)
+}
+
 func doEqualCompare(t token.Token, equal bool) (Object, bool) {
 	if t == token.EQ {
 		return NewBoolean(equal), true
@@ -86,3 +86,22 @@ func (s *StringObject) OnIndex(o Object) (Object, bool) {
 
 	return r, ok
 }
+
+// Strings are immutable.
+func (s *StringObject) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+// OnSlice makes a substring by bytes, like OnIndex.
+func (s *StringObject) OnSlice(low Object, high Object) (Object, bool) {
+	l, h, ok := sliceBounds(low, high, len(s.Value))
+	if !ok {
+		return nil, false
+	}
+
+	return NewString(s.Value[l:h]), true
+}
+
+func (s *StringObject) OnMethod(name string) (Object, bool) {
+	return lookupMethod(s, name)
+}
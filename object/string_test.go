@@ -84,3 +84,31 @@ func TestStringObjectIndexEvaluation(t *testing.T) {
 
 	testObjectEvaluation(t, tests)
 }
+
+func TestStringObjectSliceEvaluation(t *testing.T) {
+	s := NewString("foobar")
+	null := NewNull()
+
+	tests := []testObjectEvaluationCase{
+		evalTest("STRING(foobar)[INTEGER(1):INTEGER(3)]").
+			call(s.OnSlice(NewInteger(1), NewInteger(3))).
+			expect(NewString("oo"), true),
+		evalTest("STRING(foobar)[:INTEGER(-2)]").
+			call(s.OnSlice(null, NewInteger(-2))).
+			expect(NewString("foob"), true),
+		evalTest("STRING(foobar)[INTEGER(-3):]").
+			call(s.OnSlice(NewInteger(-3), null)).
+			expect(NewString("bar"), true),
+		evalTest("STRING(foobar)[INTEGER(4):INTEGER(2)]").
+			call(s.OnSlice(NewInteger(4), NewInteger(2))).
+			expect(NewString(""), true),
+		evalTest("STRING(foobar)[INTEGER(-10):INTEGER(10)]").
+			call(s.OnSlice(NewInteger(-10), NewInteger(10))).
+			expect(NewString("foobar"), true),
+		evalTest("STRING(foobar)[STRING(a):]").
+			call(s.OnSlice(NewString("a"), null)).
+			expect(nil, false),
+	}
+
+	testObjectEvaluation(t, tests)
+}
@@ -0,0 +1,135 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/flily/macaque-lang/token"
+)
+
+// UserBehavior defines how a user value acts in scripts. Each method returns
+// false if the operation is not accepted, and the default behavior is used.
+type UserBehavior interface {
+	OnPrefix(self *UserValue, t token.Token) (Object, bool)
+	OnInfix(self *UserValue, t token.Token, o Object) (Object, bool)
+	OnIndex(self *UserValue, index Object) (Object, bool)
+	OnMethod(self *UserValue, name string) (Object, bool)
+}
+
+// BaseUserBehavior accepts no operation. It is embedded by behaviors which
+// only implement some of operations.
+type BaseUserBehavior struct{}
+
+func (b BaseUserBehavior) OnPrefix(self *UserValue, t token.Token) (Object, bool) {
+	return nil, false
+}
+
+func (b BaseUserBehavior) OnInfix(self *UserValue, t token.Token, o Object) (Object, bool) {
+	return nil, false
+}
+
+func (b BaseUserBehavior) OnIndex(self *UserValue, index Object) (Object, bool) {
+	return nil, false
+}
+
+func (b BaseUserBehavior) OnMethod(self *UserValue, name string) (Object, bool) {
+	return nil, false
+}
+
+// UserValue holds a Go value provided by host code, like a handle of database
+// connection. Scripts can not look into the value, but only operate it by its
+// behavior.
+type UserValue struct {
+	Name     string
+	Value    interface{}
+	Behavior UserBehavior
+}
+
+// NewUserValue wraps value with name of its kind, behavior can be nil if the
+// value is only passed through scripts.
+func NewUserValue(name string, value interface{}, behavior UserBehavior) *UserValue {
+	if behavior == nil {
+		behavior = BaseUserBehavior{}
+	}
+
+	u := &UserValue{
+		Name:     name,
+		Value:    value,
+		Behavior: behavior,
+	}
+
+	return u
+}
+
+func (u *UserValue) Type() ObjectType {
+	return ObjectTypeUserValue
+}
+
+func (u *UserValue) Inspect() string {
+	return fmt.Sprintf("user[%s]", u.Name)
+}
+
+func (u *UserValue) Hashable() bool {
+	return false
+}
+
+func (u *UserValue) HashKey() interface{} {
+	return nil
+}
+
+func (u *UserValue) EqualTo(o Object) bool {
+	switch v := o.(type) {
+	case *UserValue:
+		return u == v
+	}
+
+	return false
+}
+
+func (u *UserValue) OnPrefix(t token.Token) (Object, bool) {
+	if r, ok := u.Behavior.OnPrefix(u, t); ok {
+		return r, true
+	}
+
+	var r Object
+	ok := false
+	switch t {
+	case token.Bang:
+		r, ok = objectFalse, true
+	}
+
+	return r, ok
+}
+
+func (u *UserValue) OnInfix(t token.Token, o Object) (Object, bool) {
+	if r, ok := u.Behavior.OnInfix(u, t, o); ok {
+		return r, true
+	}
+
+	if t == token.EQ || t == token.NE {
+		return doEqualCompare(t, u.EqualTo(o))
+	}
+
+	return nil, false
+}
+
+func (u *UserValue) OnIndex(index Object) (Object, bool) {
+	return u.Behavior.OnIndex(u, index)
+}
+
+func (u *UserValue) OnAssignIndex(index Object, value Object) bool {
+	return false
+}
+
+func (u *UserValue) OnSlice(low Object, high Object) (Object, bool) {
+	return nil, false
+}
+
+// OnMethod finds method name in behavior of the value first, and then the
+// methods registered for all user values.
+func (u *UserValue) OnMethod(name string) (Object, bool) {
+	if m, ok := u.Behavior.OnMethod(u, name); ok {
+		return m, true
+	}
+
+	return lookupMethod(u, name)
+}
@@ -0,0 +1,147 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/token"
+)
+
+// testCounter is a user value behavior, which counts by its Go value.
+type testCounter struct {
+	BaseUserBehavior
+}
+
+func (c testCounter) OnPrefix(self *UserValue, t token.Token) (Object, bool) {
+	if t == token.Minus {
+		return NewInteger(-int64(*self.Value.(*int))), true
+	}
+
+	return nil, false
+}
+
+func (c testCounter) OnInfix(self *UserValue, t token.Token, o Object) (Object, bool) {
+	n, ok := o.(*IntegerObject)
+	if t != token.Plus || !ok {
+		return nil, false
+	}
+
+	return NewInteger(int64(*self.Value.(*int)) + n.Value), true
+}
+
+func (c testCounter) OnIndex(self *UserValue, index Object) (Object, bool) {
+	if s, ok := index.(*StringObject); ok && s.Value == "count" {
+		return NewInteger(int64(*self.Value.(*int))), true
+	}
+
+	return nil, false
+}
+
+func (c testCounter) OnMethod(self *UserValue, name string) (Object, bool) {
+	if name != "incr" {
+		return nil, false
+	}
+
+	m := &MethodObject{
+		Name: "incr",
+		Method: func(c Caller, self Object, args []Object) ([]Object, error) {
+			p := self.(*UserValue).Value.(*int)
+			*p++
+			return []Object{self}, nil
+		},
+	}
+
+	return m, true
+}
+
+func TestUserValue(t *testing.T) {
+	n := 3
+	u := NewUserValue("counter", &n, testCounter{})
+
+	if u.Type() != ObjectTypeUserValue || u.Type().String() != "USERVALUE" {
+		t.Errorf("wrong type of user value: %s", u.Type())
+	}
+
+	if u.Inspect() != "user[counter]" {
+		t.Errorf("wrong user value inspect: %s", u.Inspect())
+	}
+
+	if u.Hashable() || u.HashKey() != nil {
+		t.Errorf("user value must not be hashable")
+	}
+
+	v := NewUserValue("counter", &n, testCounter{})
+	if !u.EqualTo(u) || u.EqualTo(v) || u.EqualTo(NewInteger(3)) {
+		t.Errorf("user values are equal only to themselves")
+	}
+}
+
+func TestUserValueBehavior(t *testing.T) {
+	n := 3
+	u := NewUserValue("counter", &n, testCounter{})
+
+	if r, ok := u.OnPrefix(token.Minus); !ok || !r.EqualTo(NewInteger(-3)) {
+		t.Errorf("-u got %v, %v", r, ok)
+	}
+
+	if r, ok := u.OnPrefix(token.Bang); !ok || !r.EqualTo(NewBoolean(false)) {
+		t.Errorf("!u got %v, %v", r, ok)
+	}
+
+	if r, ok := u.OnInfix(token.Plus, NewInteger(2)); !ok || !r.EqualTo(NewInteger(5)) {
+		t.Errorf("u + 2 got %v, %v", r, ok)
+	}
+
+	if _, ok := u.OnInfix(token.Minus, NewInteger(2)); ok {
+		t.Errorf("u - 2 must not be accepted")
+	}
+
+	if r, ok := u.OnInfix(token.EQ, u); !ok || !r.EqualTo(NewBoolean(true)) {
+		t.Errorf("u == u got %v, %v", r, ok)
+	}
+
+	if r, ok := u.OnIndex(NewString("count")); !ok || !r.EqualTo(NewInteger(3)) {
+		t.Errorf("u[\"count\"] got %v, %v", r, ok)
+	}
+
+	if _, ok := u.OnIndex(NewString("other")); ok {
+		t.Errorf("u[\"other\"] must not be accepted")
+	}
+
+	if u.OnAssignIndex(NewString("count"), NewInteger(1)) {
+		t.Errorf("user value must not be assigned by index")
+	}
+
+	if _, err := callMethod(u, "incr"); err != nil || n != 4 {
+		t.Errorf("u::incr() got %d, %v", n, err)
+	}
+
+	if _, ok := u.OnMethod("decr"); ok {
+		t.Errorf("u::decr must not be found")
+	}
+}
+
+func TestUserValueWithoutBehavior(t *testing.T) {
+	u := NewUserValue("handle", "conn", nil)
+
+	if _, ok := u.OnPrefix(token.Minus); ok {
+		t.Errorf("-u must not be accepted")
+	}
+
+	if _, ok := u.OnInfix(token.Plus, NewInteger(1)); ok {
+		t.Errorf("u + 1 must not be accepted")
+	}
+
+	if _, ok := u.OnIndex(NewInteger(0)); ok {
+		t.Errorf("u[0] must not be accepted")
+	}
+
+	RegisterMethod(ObjectTypeUserValue, "test_name", func(c Caller, self Object, args []Object) ([]Object, error) {
+		return []Object{NewString(self.(*UserValue).Name)}, nil
+	})
+	defer delete(methodTables[ObjectTypeUserValue], "test_name")
+
+	got, err := callMethod(u, "test_name")
+	if err != nil || !got.EqualTo(NewString("handle")) {
+		t.Errorf("u::test_name() got %v, %v", got, err)
+	}
+}
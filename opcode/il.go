@@ -17,7 +17,9 @@ func NewIL(code int, ops ...interface{}) IL {
 	var err string
 
 	switch code {
-	case INOP, ILoadNull, IIndex, IClean, IReturn, IHalt, IScopeIn, IStackRev:
+	case INOP, ILoadNull, IIndex, IClean, IReturn, IHalt, IScopeIn, IStackRev, ISDUP,
+		IEndTry, IThrow, ICallV, ITailCallV, ISpread, ISetIndex, IMakeCell, IDeref, ISetRef,
+		IBreak, IContinue, IIter, ISlice, ILen:
 		r = ilCodeOp0(code)
 		if len(ops) > 0 {
 			err = fmt.Sprintf("code %s(%d) MUST NOT have operands", CodeName(code), code)
@@ -25,14 +27,25 @@ func NewIL(code int, ops ...interface{}) IL {
 
 	case IMakeFunc:
 		var function, bindings int
+		var info *Function
 		var ok bool
 		if len(ops) != 2 {
 			err = fmt.Sprintf("code %s(%d) MUST have 2 operands", CodeName(code), code)
 			break
 		}
 
-		if function, ok = ops[0].(int); !ok {
-			err = fmt.Sprintf("operand 0 MUST be int, got %T", ops[0])
+		// Function can be referenced by its information, the index is resolved
+		// when the function is linked into a code page.
+		switch f := ops[0].(type) {
+		case int:
+			function = f
+		case *Function:
+			info = f
+		default:
+			err = fmt.Sprintf("operand 0 MUST be int or *Function, got %T", ops[0])
+		}
+
+		if err != "" {
 			break
 		}
 
@@ -44,6 +57,7 @@ func NewIL(code int, ops ...interface{}) IL {
 		r = ilCodeMakeFunc{
 			Function: function,
 			Bindings: bindings,
+			Info:     info,
 		}
 
 	default:
@@ -125,12 +139,20 @@ func (i ilCodeMakeFunc) GetCode() int {
 	return IMakeFunc
 }
 
+func (i ilCodeMakeFunc) index() int {
+	if i.Info != nil {
+		return int(i.Info.GlobalIndex)
+	}
+
+	return i.Function
+}
+
 func (i ilCodeMakeFunc) GetOpcode() Opcode {
-	return Code(IMakeFunc, i.Function, i.Bindings)
+	return Code(IMakeFunc, i.index(), i.Bindings)
 }
 
 func (i ilCodeMakeFunc) String() string {
-	return fmt.Sprintf("%s %d %d", CodeName(IMakeFunc), i.Function, i.Bindings)
+	return fmt.Sprintf("%s %d %d", CodeName(IMakeFunc), i.index(), i.Bindings)
 }
 
 func (i ilCodeMakeFunc) elemCodeBlock() {}
@@ -6,65 +6,137 @@ import (
 
 const (
 	// Opcodes should be less than 8 characters.
-	IInvalid  = iota
-	INOP      // No operation.
-	ILoadInt  // Load an integer to the top of the stack.
-	ILoadNull // Load a NULL to the top of the stack.
-	ILoadBool // Load a boolean to the top of the stack.
-	ILoadBind // Load a variable from function bound varaible to the top of the stack.
-	ILoad     // Load a variable from data segment to the top of the stack.
-	IPop      // Pop the top of the stack.
-	ISLoad    // Load a variable from stack frame to the top of the stack.
-	ISStore   // Store TOS to a local variable
-	ISDUP     // Duplicate the top of the stack.
-	IStackRev // Reverse the stack.
-	IBinOp    // Binary operation.
-	IUniOp    // Unary operation.
-	IMakeList // Make a list.
-	IMakeHash // Make a hash.
-	IMakeFunc // Make a function.
-	IIndex    // Get item of a list or a hash.
-	IJump     // Jump to a position.
-	IJumpIf   // Jump to a position if TOS is false
-	IJumpFWD  // Jump forward.
-	IScopeIn  // Enter a new scope.
-	IScopeOut // Leave a scope.
-	ICall     // Call a function.
-	IClean    // Clean the stack.
-	IReturn   // Return from a function.
-	IHalt     // Halt the VM.
-	ILastInst // Last instruction, no use.
+	IInvalid   = iota
+	INOP       // No operation.
+	ILoadInt   // Load an integer to the top of the stack.
+	ILoadNull  // Load a NULL to the top of the stack.
+	ILoadBool  // Load a boolean to the top of the stack.
+	ILoadBind  // Load a variable from function bound varaible to the top of the stack.
+	ILoad      // Load a variable from data segment to the top of the stack.
+	INative    // Load a native function provided by host.
+	IPop       // Pop the top of the stack.
+	ISLoad     // Load a variable from stack frame to the top of the stack.
+	ISStore    // Store TOS to a local variable
+	IMLoad     // Load a module variable to the top of the stack.
+	IMStore    // Store TOS to a module variable.
+	IGLoad     // Load a global variable to the top of the stack.
+	IGStore    // Store TOS to a global variable.
+	ISDUP      // Duplicate the top of the stack.
+	IStackRev  // Reverse the stack.
+	IBinOp     // Binary operation.
+	IUniOp     // Unary operation.
+	IMakeList  // Make a list.
+	IMakeHash  // Make a hash.
+	IMakeFunc  // Make a function.
+	IIndex     // Get item of a list or a hash.
+	ISetIndex  // Set item of a list or a hash.
+	ISlice     // Get slice of a list or a string.
+	IIsType    // Test whether type of TOS is the operand.
+	ILen       // Get length of a list or a hash.
+	IConcat    // Concatenate values on the stack to a string.
+	IMakeCell  // Make a cell holding TOS, for mutable variable.
+	IDeref     // Replace the cell on TOS with its value.
+	ISetRef    // Set value of the cell on TOS with TOS-1.
+	IJump      // Jump backward or forward.
+	IJumpIf    // Jump to a position if TOS is false
+	IJumpFWD   // Jump forward.
+	IScopeIn   // Enter a new scope.
+	IScopeOut  // Leave a scope.
+	ILoopIn    // Enter a scope of loop body.
+	IBreak     // Leave the loop.
+	IContinue  // Leave the loop body, and start the next iteration.
+	IIter      // Make an iterator of TOS.
+	INext      // Push the next element of the iterator on TOS.
+	ICall      // Call a function.
+	ITailCall  // Call a function in tail position, reuse the current frame.
+	ICallV     // Call a function with all values in the current scope as arguments.
+	ITailCallV // Call a function in tail position with all values in the current scope.
+	IMethod    // Push method of TOS.
+	ISpread    // Spread elements of TOS array onto the stack.
+	IImport    // Import a module, run its main function at the first time.
+	IPCall     // Call a function in protected mode.
+	IXPCall    // Call a function in protected mode, with a message handler.
+	ITraceback // Get positions of all functions in call stack.
+	ITypeOf    // Replace TOS with name of its type.
+	IFuncInfo  // Replace the function on TOS with a hash of its information.
+	IPrint     // Write values on the stack to output of VM.
+	IPrintLn   // Write values on the stack to output of VM, with a newline.
+	IPrintf    // Write values on the stack to output of VM, in format of TOS.
+	IReadLine  // Read a line from input of VM.
+	ITry       // Install an exception handler.
+	IEndTry    // Remove the last exception handler.
+	IThrow     // Throw TOS as an exception.
+	IClean     // Clean the stack.
+	IReturn    // Return from a function.
+	IHalt      // Halt the VM.
+	ILastInst  // Last instruction, no use.
 )
 
 var codeNames = [...]string{
-	IInvalid:  "INVALID",
-	INOP:      "NOP",
-	ILoadInt:  "LOADINT",
-	ILoadNull: "LOADNULL",
-	ILoadBool: "LOADBOOL",
-	ILoadBind: "LOADBIND",
-	ILoad:     "LOAD",
-	IPop:      "POP",
-	ISLoad:    "SLOAD",
-	ISStore:   "SSTORE",
-	IStackRev: "STACKREV",
-	IBinOp:    "BINOP",
-	IUniOp:    "UNIOP",
-	IMakeList: "MAKELIST",
-	IMakeHash: "MAKEHASH",
-	IMakeFunc: "MAKEFUNC",
-	IIndex:    "INDEX",
-	IJump:     "JUMP",
-	IJumpFWD:  "JUMFWD",
-	IJumpIf:   "JUMPIF",
-	ISDUP:     "SDUP",
-	IScopeIn:  "SCOPEIN",
-	IScopeOut: "SCOPEOUT",
-	ICall:     "CALL",
-	IClean:    "CLEAN",
-	IReturn:   "RETURN",
-	IHalt:     "HALT",
-	ILastInst: "LASTINST",
+	IInvalid:   "INVALID",
+	INOP:       "NOP",
+	ILoadInt:   "LOADINT",
+	ILoadNull:  "LOADNULL",
+	ILoadBool:  "LOADBOOL",
+	ILoadBind:  "LOADBIND",
+	ILoad:      "LOAD",
+	INative:    "NATIVE",
+	IPop:       "POP",
+	ISLoad:     "SLOAD",
+	ISStore:    "SSTORE",
+	IMLoad:     "MLOAD",
+	IMStore:    "MSTORE",
+	IGLoad:     "GLOAD",
+	IGStore:    "GSTORE",
+	IStackRev:  "STACKREV",
+	IBinOp:     "BINOP",
+	IUniOp:     "UNIOP",
+	IMakeList:  "MAKELIST",
+	IMakeHash:  "MAKEHASH",
+	IMakeFunc:  "MAKEFUNC",
+	IIndex:     "INDEX",
+	ISetIndex:  "SETINDEX",
+	ISlice:     "SLICE",
+	IIsType:    "ISTYPE",
+	ILen:       "LEN",
+	IConcat:    "CONCAT",
+	IMakeCell:  "MAKECELL",
+	IDeref:     "DEREF",
+	ISetRef:    "SETREF",
+	IJump:      "JUMP",
+	IJumpFWD:   "JUMFWD",
+	IJumpIf:    "JUMPIF",
+	ISDUP:      "SDUP",
+	IScopeIn:   "SCOPEIN",
+	IScopeOut:  "SCOPEOUT",
+	ILoopIn:    "LOOPIN",
+	IBreak:     "BREAK",
+	IContinue:  "CONTINUE",
+	IIter:      "ITER",
+	INext:      "NEXT",
+	ICall:      "CALL",
+	ITailCall:  "TAILCALL",
+	ICallV:     "CALLV",
+	ITailCallV: "TAILCALLV",
+	IMethod:    "METHOD",
+	ISpread:    "SPREAD",
+	IImport:    "IMPORT",
+	IPCall:     "PCALL",
+	IXPCall:    "XPCALL",
+	ITraceback: "TRACE",
+	ITypeOf:    "TYPEOF",
+	IFuncInfo:  "FUNCINFO",
+	IPrint:     "PRINT",
+	IPrintLn:   "PRINTLN",
+	IPrintf:    "PRINTF",
+	IReadLine:  "READLINE",
+	ITry:       "TRY",
+	IEndTry:    "ENDTRY",
+	IThrow:     "THROW",
+	IClean:     "CLEAN",
+	IReturn:    "RETURN",
+	IHalt:      "HALT",
+	ILastInst:  "LASTINST",
 }
 
 func CodeName(code int) string {
@@ -129,8 +129,10 @@ func (b *CodeBlock) Link(postfixes ...Opcode) ([]Opcode, []*token.Context) {
 type Function struct {
 	ModuleIndex  uint64
 	GlobalIndex  uint64
+	Module       int // index of module + 1, ZERO for the main program
 	FrameSize    int
 	Arguments    int
+	Variadic     bool // last argument collects extra arguments as an array
 	ReturnValues int
 	IP           uint64
 	Codes        *CodeBlock
@@ -143,6 +145,7 @@ func (f *Function) Func(bounds []object.Object) *object.FunctionObject {
 		Index:     f.GlobalIndex,
 		FrameSize: f.FrameSize,
 		Arguments: f.Arguments,
+		Variadic:  f.Variadic,
 		IP:        f.IP,
 		Bounds:    bounds,
 	}
@@ -215,6 +218,7 @@ type CodePage struct {
 	ModuleNameMap map[string]*Module
 	Functions     []*Function
 	Data          []object.Object
+	Natives       []string // names of native functions, by index of NATIVE
 }
 
 func NewCodePage() *CodePage {
@@ -227,8 +231,20 @@ func (p *CodePage) Main() *Function {
 	return p.Functions[0]
 }
 
+func (p *CodePage) AddModule(m *Module) {
+	p.NativeModules = append(p.NativeModules, m)
+	p.ModuleNameMap[m.Canonical] = m
+}
+
+// LinkFunctions merges functions of all modules into the code page, after the
+// functions of main module. Functions are referenced by their global index, so
+// all modules MUST be added before the code is linked.
+// Data of modules are not merged here, all modules in a code page are compiled
+// with the same literal table, which is already the Data of the code page.
 func (p *CodePage) LinkFunctions() {
-	p.Functions = make([]*Function, 1)
+	if len(p.Functions) <= 0 {
+		p.Functions = make([]*Function, 1)
+	}
 
 	for _, m := range p.NativeModules {
 		for _, f := range m.Functions {
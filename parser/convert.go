@@ -2,6 +2,7 @@ package parser
 
 import (
 	"strconv"
+	"strings"
 )
 
 func ConvertDecimalInteger(content string) int64 {
@@ -77,10 +78,15 @@ func ConvertFloat(content string) float64 {
 }
 
 func convertDoubleQuoteString(content string) string {
+	return unescapeString(content[1:]) // skip the first quote
+}
+
+// unescapeString converts escape sequences in content, until an unescaped '"'.
+func unescapeString(content string) string {
 	length := len(content) // In golang spec, len() returns the number of bytes.
 	buffer := make([]byte, length)
 
-	i := 1 // index of content, skip the first quote
+	i := 0 // index of content
 	j := 0 // index of buffer
 	finished := false
 
@@ -106,6 +112,8 @@ func convertDoubleQuoteString(content string) string {
 				buffer[j] = '\\'
 			case '"':
 				buffer[j] = '"'
+			case '$':
+				buffer[j] = '$'
 			case 'x':
 				code := content[i : i+2]
 				buffer[j] = byte(convertHexdecimalInteger(code))
@@ -122,6 +130,121 @@ func convertDoubleQuoteString(content string) string {
 	return string(buffer[:j])
 }
 
+// stringPart is a part of string literal with interpolation, which is a piece
+// of characters or an embedded expression in "${}". Offset is the index of the
+// part in content of string literal.
+type stringPart struct {
+	Content  string
+	Offset   int
+	Embedded bool
+}
+
+// splitInterpolation splits content of a double-quoted string literal into
+// pieces of characters and embedded expressions. It returns nil if there is no
+// interpolation in the string.
+func splitInterpolation(content string) []stringPart {
+	if content[0] != '"' {
+		return nil // raw string
+	}
+
+	var parts []stringPart
+	length := len(content)
+	embedded := false
+
+	start := 1 // skip the first quote
+	i := start
+	for i < length && content[i] != '"' {
+		switch {
+		case content[i] == '\\':
+			i += 2
+
+		case content[i] == '$' && i+1 < length && content[i+1] == '{':
+			if i > start {
+				parts = append(parts, stringPart{Content: content[start:i], Offset: start})
+			}
+
+			end := skipInterpolation(content, i+2)
+			parts = append(parts, stringPart{
+				Content:  content[i+2 : end],
+				Offset:   i + 2,
+				Embedded: true,
+			})
+			embedded = true
+			i = end + 1
+			start = i
+
+		default:
+			i++
+		}
+	}
+
+	if !embedded {
+		return nil
+	}
+
+	if i > length {
+		i = length
+	}
+
+	if i > start {
+		parts = append(parts, stringPart{Content: content[start:i], Offset: start})
+	}
+
+	return parts
+}
+
+// skipInterpolation returns index of the '}' which closes an embedded
+// expression starting at i.
+func skipInterpolation(content string, i int) int {
+	depth := 1
+	for i < len(content) {
+		switch content[i] {
+		case '{':
+			depth++
+
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+
+		case '"':
+			i = skipString(content, i+1)
+			continue
+
+		case '`':
+			if end := strings.IndexByte(content[i+1:], '`'); end >= 0 {
+				i += end + 1
+			}
+		}
+
+		i++
+	}
+
+	return len(content)
+}
+
+// skipString returns index after the '"' which closes a string starting at i.
+func skipString(content string, i int) int {
+	for i < len(content) {
+		switch {
+		case content[i] == '\\':
+			i += 2
+
+		case content[i] == '"':
+			return i + 1
+
+		case content[i] == '$' && i+1 < len(content) && content[i+1] == '{':
+			i = skipInterpolation(content, i+2) + 1
+
+		default:
+			i++
+		}
+	}
+
+	return len(content)
+}
+
 func ConvertString(content string) string {
 	var result string
 	quote := content[0]
@@ -129,6 +252,9 @@ func ConvertString(content string) string {
 	switch quote {
 	case '"':
 		result = convertDoubleQuoteString(content)
+
+	case '`':
+		result = content[1 : len(content)-1]
 	}
 
 	return result
@@ -150,6 +150,9 @@ func TestConvertString(t *testing.T) {
 		{`"hello \"world\""`, `hello "world"`},
 		{`"hello \n world"`, "hello \n world"},
 		{`"hello \r\n\t\\\"\x42`, "hello \r\n\t\\\"\x42"},
+		{`"\${price} is \$5"`, "${price} is $5"},
+		{"`raw \\n ${x}\n\"line\"`", "raw \\n ${x}\n\"line\""},
+		{"``", ""},
 	}
 
 	for _, test := range tests {
@@ -160,3 +163,51 @@ func TestConvertString(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []stringPart
+	}{
+		{`"hello"`, nil},
+		{`"\${a}$b"`, nil},
+		{"`${a}`", nil},
+		{
+			`"hello ${name}, ${age + 1}"`,
+			[]stringPart{
+				{"hello ", 1, false},
+				{"name", 9, true},
+				{", ", 14, false},
+				{"age + 1", 18, true},
+			},
+		},
+		{
+			`"${ {"k": "}${x}"}.k }!"`,
+			[]stringPart{
+				{` {"k": "}${x}"}.k `, 3, true},
+				{"!", 22, false},
+			},
+		},
+		{
+			"\"${ `}` + a }\"",
+			[]stringPart{
+				{" `}` + a ", 3, true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := splitInterpolation(test.input)
+		if len(got) != len(test.expected) {
+			t.Fatalf("splitInterpolation(%s) got %d parts, expected %d: %v",
+				test.input, len(got), len(test.expected), got)
+		}
+
+		for i, part := range got {
+			if part != test.expected[i] {
+				t.Errorf("splitInterpolation(%s) part %d is %v, expected %v",
+					test.input, i, part, test.expected[i])
+			}
+		}
+	}
+}
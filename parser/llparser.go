@@ -20,6 +20,7 @@ var expressionFirstSet = [...]bool{
 	token.LBracket:   true,
 	token.LBrace:     true,
 	token.If:         true,
+	token.Match:      true,
 	token.Fn:         true,
 	token.LastToken:  false,
 }
@@ -30,6 +31,8 @@ func isExpressionFirstSet(token token.Token) bool {
 
 const (
 	RuleLetStatement        = "let statement"
+	RuleVarStatement        = "var statement"
+	RuleAssignExpression    = "assign expression"
 	RuleExpressionStatement = "expression statement"
 	RuleReturnStatement     = "return statement"
 	RuleBlockStatement      = "block statement"
@@ -42,8 +45,22 @@ const (
 	RuleHashLiteral         = "hash literal"
 	RuleCallExpression      = "call expression"
 	RuleIndexExpression     = "index expression"
+	RuleSliceExpression     = "slice expression"
+	RuleArrayPattern        = "array pattern"
+	RuleHashPattern         = "hash pattern"
 	RuleGroupedExpression   = "grouped expression"
 	RuleIfExpression        = "if expression"
+	RuleMatchExpression     = "match expression"
+	RuleMatchPattern        = "match pattern"
+	RuleImportStatement     = "import statement"
+	RuleThrowStatement      = "throw statement"
+	RuleTryStatement        = "try statement"
+	RuleSpreadExpression    = "spread expression"
+	RuleWhileStatement      = "while statement"
+	RuleForStatement        = "for statement"
+	RuleBreakStatement      = "break statement"
+	RuleContinueStatement   = "continue statement"
+	RuleStringInterpolation = "string interpolation"
 )
 
 type LLParser struct {
@@ -198,27 +215,49 @@ func (p *LLParser) parseStatement(context string) (ast.Statement, error) {
 
 	current, comments := p.currentSkipComment()
 	switch current.Token {
-	case token.Let:
+	case token.Let, token.Var:
 		stmt, err = p.parseLetStatement()
 
 	case token.Null, token.False, token.True, token.Integer, token.Float, token.String,
 		token.Identifier, token.Minus, token.Bang, token.LParen, token.LBracket, token.LBrace,
-		token.If, token.Fn:
+		token.If, token.Match, token.Fn:
 		stmt, err = p.parseExpressionStatement()
 
 	case token.Return:
 		stmt, err = p.parseReturnStatement()
 
+	case token.Import:
+		stmt, err = p.parseImportStatement()
+
+	case token.Throw:
+		stmt, err = p.parseThrowStatement()
+
+	case token.Try:
+		stmt, err = p.parseTryStatement()
+
+	case token.While:
+		stmt, err = p.parseWhileStatement()
+
+	case token.For:
+		stmt, err = p.parseForStatement()
+
+	case token.Break:
+		stmt, err = p.parseBreakStatement()
+
+	case token.Continue:
+		stmt, err = p.parseContinueStatement()
+
 	case token.EOF:
 		stmt, err = nil, nil
 
 	default:
 		expects := []token.Token{
-			token.Let, token.Comment,
+			token.Let, token.Var, token.Comment,
 			token.Null, token.False, token.True, token.Integer, token.Float, token.String,
 			token.Identifier, token.Minus, token.Bang, token.LParen, token.LBracket, token.LBrace,
-			token.If, token.Fn,
-			token.Return,
+			token.If, token.Match, token.Fn,
+			token.Return, token.Import, token.Throw, token.Try,
+			token.While, token.For, token.Break, token.Continue,
 		}
 
 		err = p.unexpectedError(context, expects)
@@ -232,17 +271,38 @@ func (p *LLParser) parseStatement(context string) (ast.Statement, error) {
 	return stmt, nil
 }
 
-// let-stmt => "let" identifier-list "=" expression-list ";"
+// let-stmt => "let" ( identifier-list / pattern ) "=" expression-list ";"
+// var-stmt => "var" ( identifier-list / pattern ) "=" expression-list ";"
 func (p *LLParser) parseLetStatement() (*ast.LetStatement, error) {
 	var sLet, sAssign, sSemicolon *token.TokenContext
-	sLet, _ = p.skipToken(token.Let, RuleLetStatement)
+	rule := RuleLetStatement
+	if p.current().Token == token.Var {
+		rule = RuleVarStatement
+	}
+
+	sLet = p.current()
+	p.nextToken()
+
+	var idList *ast.IdentifierList
+	var pattern ast.Pattern
+	var err error
+	current, _ := p.currentSkipComment()
+	switch current.Token {
+	case token.LBracket:
+		pattern, err = p.parseArrayPattern()
+
+	case token.LBrace:
+		pattern, err = p.parseHashPattern()
+
+	default:
+		idList, err = p.parseIdentifierList()
+	}
 
-	idList, err := p.parseIdentifierList()
 	if err != nil {
 		return nil, err
 	}
 
-	if sAssign, err = p.skipTokenAndComment(token.Assign, RuleLetStatement); err != nil {
+	if sAssign, err = p.skipTokenAndComment(token.Assign, rule); err != nil {
 		return nil, err
 	}
 
@@ -251,13 +311,14 @@ func (p *LLParser) parseLetStatement() (*ast.LetStatement, error) {
 		return nil, err
 	}
 
-	if sSemicolon, err = p.skipTokenAndComment(token.Semicolon, RuleLetStatement); err != nil {
+	if sSemicolon, err = p.skipTokenAndComment(token.Semicolon, rule); err != nil {
 		return nil, err
 	}
 
 	stmt := &ast.LetStatement{
 		Let:         sLet,
 		Identifiers: idList,
+		Pattern:     pattern,
 		Assign:      sAssign,
 		Expressions: exprList,
 		Semicolon:   sSemicolon,
@@ -266,6 +327,75 @@ func (p *LLParser) parseLetStatement() (*ast.LetStatement, error) {
 	return stmt, nil
 }
 
+// array-pattern => "[" [ identifier *( "," identifier ) [","] ] [ "..." identifier ] "]"
+func (p *LLParser) parseArrayPattern() (*ast.ArrayPattern, error) {
+	var err error
+	pattern := &ast.ArrayPattern{
+		Elements: &ast.IdentifierList{},
+	}
+
+	pattern.LBracket, _ = p.skipTokenAndComment(token.LBracket, RuleArrayPattern)
+
+	current, _ := p.currentSkipComment()
+	for current.Token == token.Identifier {
+		id, _ := p.parseIdentifier()
+		comma, err := p.skipTokenAndComment(token.Comma, RuleArrayPattern)
+		pattern.Elements.AddIdentifier(id, comma)
+		if err != nil {
+			break
+		}
+
+		current, _ = p.currentSkipComment()
+	}
+
+	if pattern.Ellipsis, err = p.skipTokenAndComment(token.Ellipsis, RuleArrayPattern); err == nil {
+		if pattern.Rest, err = p.parseIdentifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	if pattern.RBracket, err = p.skipTokenAndComment(token.RBracket, RuleArrayPattern); err != nil {
+		return nil, err
+	}
+
+	return pattern, nil
+}
+
+// hash-pattern => "{" [ hash-pattern-pair *( "," hash-pattern-pair ) [","] ] "}"
+// hash-pattern-pair => identifier [ ":" identifier ]
+func (p *LLParser) parseHashPattern() (*ast.HashPattern, error) {
+	var err error
+	pattern := &ast.HashPattern{}
+
+	pattern.LBrace, _ = p.skipTokenAndComment(token.LBrace, RuleHashPattern)
+
+	current, _ := p.currentSkipComment()
+	for current.Token == token.Identifier {
+		pair := &ast.HashPatternPair{}
+		pair.Key, _ = p.parseIdentifier()
+		pair.Value = pair.Key
+
+		if pair.Colon, err = p.skipTokenAndComment(token.Colon, RuleHashPattern); err == nil {
+			if pair.Value, err = p.parseIdentifier(); err != nil {
+				return nil, err
+			}
+		}
+
+		pattern.Pairs = append(pattern.Pairs, pair)
+		if pair.Comma, err = p.skipTokenAndComment(token.Comma, RuleHashPattern); err != nil {
+			break
+		}
+
+		current, _ = p.currentSkipComment()
+	}
+
+	if pattern.RBrace, err = p.skipTokenAndComment(token.RBrace, RuleHashPattern); err != nil {
+		return nil, err
+	}
+
+	return pattern, nil
+}
+
 // expression-stmt => expression-list ";"
 func (p *LLParser) parseExpressionStatement() (*ast.ExpressionStatement, error) {
 	var sSemicolon *token.TokenContext
@@ -305,6 +435,202 @@ func (p *LLParser) parseReturnStatement() (*ast.ReturnStatement, error) {
 	return stmt, nil
 }
 
+// import-stmt => "import" [identifier] string-literal ";"
+func (p *LLParser) parseImportStatement() (*ast.ImportStatement, error) {
+	var sImport, sTarget, sSemicolon *token.TokenContext
+	var name *ast.Identifier
+	var err error
+	sImport, _ = p.skipToken(token.Import, RuleImportStatement)
+
+	current, _ := p.currentSkipComment()
+	if current.Token == token.Identifier {
+		if name, err = p.parseIdentifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	if sTarget, err = p.skipTokenAndComment(token.String, RuleImportStatement); err != nil {
+		return nil, err
+	}
+
+	sSemicolon, _ = p.skipTokenAndComment(token.Semicolon, RuleImportStatement)
+
+	stmt := &ast.ImportStatement{
+		Import:    sImport,
+		Name:      name,
+		Target:    sTarget,
+		Path:      ConvertString(sTarget.Content),
+		Semicolon: sSemicolon,
+	}
+
+	return stmt, nil
+}
+
+// throw-stmt => "throw" expression ";"
+func (p *LLParser) parseThrowStatement() (*ast.ThrowStatement, error) {
+	var sThrow, sSemicolon *token.TokenContext
+	sThrow, _ = p.skipToken(token.Throw, RuleThrowStatement)
+
+	expr, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	sSemicolon, _ = p.skipTokenAndComment(token.Semicolon, RuleThrowStatement)
+
+	stmt := &ast.ThrowStatement{
+		Throw:      sThrow,
+		Expression: expr,
+		Semicolon:  sSemicolon,
+	}
+
+	return stmt, nil
+}
+
+// try-stmt
+// => "try" block-stmt "catch" "(" identifier ")" block-stmt
+// => "try" block-stmt "finally" block-stmt
+// => "try" block-stmt "catch" "(" identifier ")" block-stmt "finally" block-stmt
+func (p *LLParser) parseTryStatement() (*ast.TryStatement, error) {
+	var err error
+	stmt := &ast.TryStatement{}
+	stmt.Try, _ = p.skipToken(token.Try, RuleTryStatement)
+
+	if err = p.expectSkipComment(token.LBrace, RuleTryStatement); err != nil {
+		return nil, err
+	}
+
+	if stmt.Body, err = p.parseBlockStatement(RuleTryStatement); err != nil {
+		return nil, err
+	}
+
+	if stmt.Catch, err = p.skipTokenAndComment(token.Catch, RuleTryStatement); err == nil {
+		if stmt.LParen, err = p.skipTokenAndComment(token.LParen, RuleTryStatement); err != nil {
+			return nil, err
+		}
+
+		if stmt.Variable, err = p.parseIdentifier(); err != nil {
+			return nil, err
+		}
+
+		if stmt.RParen, err = p.skipTokenAndComment(token.RParen, RuleTryStatement); err != nil {
+			return nil, err
+		}
+
+		if err = p.expectSkipComment(token.LBrace, RuleTryStatement); err != nil {
+			return nil, err
+		}
+
+		if stmt.CatchBody, err = p.parseBlockStatement(RuleTryStatement); err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.Finally, err = p.skipTokenAndComment(token.Finally, RuleTryStatement); err == nil {
+		if err = p.expectSkipComment(token.LBrace, RuleTryStatement); err != nil {
+			return nil, err
+		}
+
+		if stmt.FinallyBody, err = p.parseBlockStatement(RuleTryStatement); err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.CatchBody == nil && stmt.FinallyBody == nil {
+		current, _ := p.currentSkipComment()
+		expecteds := []token.Token{token.Catch, token.Finally}
+		return nil, p.unexpectedError(RuleTryStatement, expecteds).
+			WithMessage("expect token CATCH or FINALLY IN %s, but got %s",
+				RuleTryStatement, current.Token)
+	}
+
+	return stmt, nil
+}
+
+// while-stmt => "while" "(" expression ")" block-stmt
+func (p *LLParser) parseWhileStatement() (*ast.WhileStatement, error) {
+	var err error
+	stmt := &ast.WhileStatement{}
+	stmt.While, _ = p.skipToken(token.While, RuleWhileStatement)
+
+	if stmt.LParen, err = p.skipTokenAndComment(token.LParen, RuleWhileStatement); err != nil {
+		return nil, err
+	}
+
+	if stmt.Condition, err = p.parseExpression(PrecedenceLowest); err != nil {
+		return nil, err
+	}
+
+	if stmt.RParen, err = p.skipTokenAndComment(token.RParen, RuleWhileStatement); err != nil {
+		return nil, err
+	}
+
+	if err = p.expectSkipComment(token.LBrace, RuleWhileStatement); err != nil {
+		return nil, err
+	}
+
+	if stmt.Body, err = p.parseBlockStatement(RuleWhileStatement); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// for-stmt => "for" "(" identifier "in" expression ")" block-stmt
+func (p *LLParser) parseForStatement() (*ast.ForStatement, error) {
+	var err error
+	stmt := &ast.ForStatement{}
+	stmt.For, _ = p.skipToken(token.For, RuleForStatement)
+
+	if stmt.LParen, err = p.skipTokenAndComment(token.LParen, RuleForStatement); err != nil {
+		return nil, err
+	}
+
+	if stmt.Variable, err = p.parseIdentifier(); err != nil {
+		return nil, err
+	}
+
+	if stmt.In, err = p.skipTokenAndComment(token.In, RuleForStatement); err != nil {
+		return nil, err
+	}
+
+	if stmt.Iterable, err = p.parseExpression(PrecedenceLowest); err != nil {
+		return nil, err
+	}
+
+	if stmt.RParen, err = p.skipTokenAndComment(token.RParen, RuleForStatement); err != nil {
+		return nil, err
+	}
+
+	if err = p.expectSkipComment(token.LBrace, RuleForStatement); err != nil {
+		return nil, err
+	}
+
+	if stmt.Body, err = p.parseBlockStatement(RuleForStatement); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// break-stmt => "break" [";"]
+func (p *LLParser) parseBreakStatement() (*ast.BreakStatement, error) {
+	stmt := &ast.BreakStatement{}
+	stmt.Break, _ = p.skipToken(token.Break, RuleBreakStatement)
+	stmt.Semicolon, _ = p.skipTokenAndComment(token.Semicolon, RuleBreakStatement)
+
+	return stmt, nil
+}
+
+// continue-stmt => "continue" [";"]
+func (p *LLParser) parseContinueStatement() (*ast.ContinueStatement, error) {
+	stmt := &ast.ContinueStatement{}
+	stmt.Continue, _ = p.skipToken(token.Continue, RuleContinueStatement)
+	stmt.Semicolon, _ = p.skipTokenAndComment(token.Semicolon, RuleContinueStatement)
+
+	return stmt, nil
+}
+
 // block-stmt => "{" *statement "}"
 func (p *LLParser) parseBlockStatement(context string) (*ast.BlockStatement, error) {
 	var sLBrace, sRBrace *token.TokenContext
@@ -405,8 +731,7 @@ func (p *LLParser) parseLiteral() (ast.Expression, error) {
 		p.nextToken()
 
 	case token.String:
-		expr = newString(current)
-		p.nextToken()
+		expr, err = p.parseStringLiteral()
 
 	case token.True, token.False:
 		expr = newBoolean(current)
@@ -429,6 +754,74 @@ func (p *LLParser) parseLiteral() (ast.Expression, error) {
 	return expr, err
 }
 
+// string-literal => DQUOTE *( character / "${" expression "}" ) DQUOTE
+func (p *LLParser) parseStringLiteral() (ast.Expression, error) {
+	current := p.current()
+	p.nextToken()
+
+	parts := splitInterpolation(current.Content)
+	if parts == nil {
+		return newString(current), nil
+	}
+
+	expr := &ast.InterpolatedString{
+		Content: current.Content,
+		Context: current.ToContext(),
+	}
+
+	for _, part := range parts {
+		if !part.Embedded {
+			literal := &ast.StringLiteral{
+				Value:   unescapeString(part.Content),
+				Content: part.Content,
+				Context: current.ToContext(),
+			}
+			expr.Parts = append(expr.Parts, literal)
+			continue
+		}
+
+		embedded, err := parseEmbeddedExpression(current, part)
+		if err != nil {
+			return nil, err
+		}
+		expr.Parts = append(expr.Parts, embedded)
+	}
+
+	return expr, nil
+}
+
+// parseEmbeddedExpression parses an embedded expression of string literal elem,
+// positions of its tokens are in the source file of elem.
+func parseEmbeddedExpression(elem *token.TokenContext, part stringPart) (ast.Expression, error) {
+	line, column := elem.LineNo(), elem.ColumnStart()
+	for i := 0; i < part.Offset; i++ {
+		if elem.Content[i] == '\n' {
+			line, column = line+1, 1
+
+		} else {
+			column++
+		}
+	}
+
+	info := elem.Position.Line.File
+	scanner := lex.NewEmbeddedScanner(info, []byte(part.Content), line, column)
+	p := NewLLParser(scanner)
+	if err := p.ReadTokens(); err != nil {
+		return nil, err
+	}
+
+	expr, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectSkipComment(token.EOF, RuleStringInterpolation); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
 // array-literal
 // => "[" expression-list "]"
 func (p *LLParser) parseArrayLiteral() (*ast.ArrayLiteral, error) {
@@ -521,7 +914,7 @@ func (p *LLParser) parseFunctionLiteral() (ast.Expression, error) {
 		return nil, err
 	}
 
-	args, err := p.parseExpressionList(ExprListCanBeEmpty)
+	args, err := p.parseArguments()
 	if err != nil {
 		return nil, err
 	}
@@ -544,7 +937,14 @@ func (p *LLParser) parseFunctionLiteral() (ast.Expression, error) {
 		return callExpr, nil
 	}
 
-	if !args.IsIdentifierList() {
+	params, rest := args.SplitRest()
+	isParameters := params.IsIdentifierList()
+	if rest != nil {
+		_, ok := rest.Expression.(*ast.Identifier)
+		isParameters = isParameters && ok
+	}
+
+	if !isParameters {
 		err := NewSyntaxError(current.ToContext(),
 			"recursion function call MUST NOT follow by a block statement",
 		)
@@ -563,12 +963,17 @@ func (p *LLParser) parseFunctionLiteral() (ast.Expression, error) {
 	literal := &ast.FunctionLiteral{
 		Function:     sFunction,
 		LParen:       sLParen,
-		Arguments:    args.ToIdentifierList(),
+		Arguments:    params.ToIdentifierList(),
 		RParen:       sRParen,
 		Body:         body,
 		ReturnValues: -1,
 	}
 
+	if rest != nil {
+		literal.Ellipsis = rest.Ellipsis
+		literal.Rest = rest.Expression.(*ast.Identifier)
+	}
+
 	return literal, nil
 }
 
@@ -602,6 +1007,54 @@ func (p *LLParser) parseExpressionList(canBeEmpty bool) (*ast.ExpressionList, er
 	return list, err
 }
 
+// arguments => [argument *( "," argument ) [","]]
+// argument => expression / spread-expression
+func (p *LLParser) parseArguments() (*ast.ExpressionList, error) {
+	list := &ast.ExpressionList{}
+
+	current, _ := p.currentSkipComment()
+	for current.Token == token.Ellipsis || isExpressionFirstSet(current.Token) {
+		var exp ast.Expression
+		var err error
+		if current.Token == token.Ellipsis {
+			exp, err = p.parseSpreadExpression()
+		} else {
+			exp, err = p.parseExpression(PrecedenceLowest)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		comma, err := p.skipTokenAndComment(token.Comma, RuleExpressionList)
+		list.AddExpression(exp, comma)
+		if err != nil {
+			break
+		}
+
+		current, _ = p.currentSkipComment()
+	}
+
+	return list, nil
+}
+
+// spread-expression => "..." expression
+func (p *LLParser) parseSpreadExpression() (*ast.SpreadExpression, error) {
+	sEllipsis, _ := p.skipToken(token.Ellipsis, RuleSpreadExpression)
+
+	expr, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	spread := &ast.SpreadExpression{
+		Ellipsis:   sEllipsis,
+		Expression: expr,
+	}
+
+	return spread, nil
+}
+
 func (p *LLParser) parseExpression(precedence int) (ast.Expression, error) {
 	var expr ast.Expression
 	var err error
@@ -624,6 +1077,9 @@ func (p *LLParser) parseExpression(precedence int) (ast.Expression, error) {
 	case token.If:
 		expr, err = p.parseIfExpression()
 
+	case token.Match:
+		expr, err = p.parseMatchExpression()
+
 	default:
 		expecteds := []token.Token{
 			token.LParen,
@@ -631,7 +1087,7 @@ func (p *LLParser) parseExpression(precedence int) (ast.Expression, error) {
 			token.LBracket, token.LBrace, token.Fn,
 			token.Identifier,
 			token.Bang, token.Minus,
-			token.If,
+			token.If, token.Match,
 		}
 		err = p.unexpectedError("EXPRESSION", expecteds)
 	}
@@ -666,6 +1122,9 @@ func (p *LLParser) parseExpressionWithOperator(expr ast.Expression, precedence i
 	case token.Period:
 		expr, err = p.parseIndexExpressionPeriodNotation(expr)
 
+	case token.Assign:
+		expr, err = p.parseAssignExpression(expr)
+
 	default:
 		if IsInfixOperator(current.Token) {
 			expr, err = p.parseInfixExpression(expr, precedence)
@@ -752,7 +1211,7 @@ func (p *LLParser) parseCallExpression(callable ast.Expression, findMethod bool)
 		sLParen, _ = p.skipToken(token.LParen, RuleCallExpression)
 	}
 
-	arguments, err := p.parseExpressionList(ExprListCanBeEmpty)
+	arguments, err := p.parseArguments()
 	if err != nil {
 		return nil, err
 	}
@@ -774,15 +1233,22 @@ func (p *LLParser) parseCallExpression(callable ast.Expression, findMethod bool)
 }
 
 // index-expression => expression "[" expression "]"
+// slice-expression => expression "[" [expression] ":" [expression] "]"
 func (p *LLParser) parseIndexExpressionBracketNotaion(base ast.Expression) (ast.Expression, error) {
 	var err error
 	var index ast.Expression
 
 	lb, _ := p.skipToken(token.LBracket, RuleIndexExpression)
 
-	index, err = p.parseExpression(PrecedenceLowest)
-	if err != nil {
-		return nil, err
+	if current, _ := p.currentSkipComment(); current.Token != token.Colon {
+		index, err = p.parseExpression(PrecedenceLowest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if colon, err := p.skipTokenAndComment(token.Colon, RuleSliceExpression); err == nil {
+		return p.parseSliceExpression(base, lb, index, colon)
 	}
 
 	var rb *token.TokenContext
@@ -800,6 +1266,35 @@ func (p *LLParser) parseIndexExpressionBracketNotaion(base ast.Expression) (ast.
 	return p.parseExpressionWithOperator(expr, PrecedenceIndex)
 }
 
+func (p *LLParser) parseSliceExpression(base ast.Expression, lb *token.TokenContext,
+	low ast.Expression, colon *token.TokenContext) (ast.Expression, error) {
+	var err error
+	var high ast.Expression
+
+	if current, _ := p.currentSkipComment(); current.Token != token.RBracket {
+		high, err = p.parseExpression(PrecedenceLowest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var rb *token.TokenContext
+	if rb, err = p.skipTokenAndComment(token.RBracket, RuleSliceExpression); err != nil {
+		return nil, err
+	}
+
+	expr := &ast.SliceExpression{
+		Base:     base,
+		LBracket: lb,
+		Low:      low,
+		Colon:    colon,
+		High:     high,
+		RBracket: rb,
+	}
+
+	return p.parseExpressionWithOperator(expr, PrecedenceIndex)
+}
+
 // index-expression => expression "." identifier
 func (p *LLParser) parseIndexExpressionPeriodNotation(base ast.Expression) (ast.Expression, error) {
 	var err error
@@ -821,6 +1316,34 @@ func (p *LLParser) parseIndexExpressionPeriodNotation(base ast.Expression) (ast.
 	return p.parseExpressionWithOperator(expr, PrecedenceIndex)
 }
 
+// assign-expression => ( identifier / index-expression ) "=" expression
+func (p *LLParser) parseAssignExpression(target ast.Expression) (ast.Expression, error) {
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+
+	default:
+		err := NewSyntaxError(target.GetContext(),
+			"%s can not be assigned", target.CanonicalCode())
+		return nil, err
+	}
+
+	sAssign, _ := p.skipToken(token.Assign, RuleAssignExpression)
+
+	// Assignment is right associative, a = b = c is a = (b = c).
+	value, err := p.parseExpression(PrecedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &ast.AssignExpression{
+		Target: target,
+		Assign: sAssign,
+		Value:  value,
+	}
+
+	return expr, nil
+}
+
 // group-expression => "(" expression ")"
 func (p *LLParser) parseGroupExpression() (ast.Expression, error) {
 	if _, err := p.skipToken(token.LParen, RuleGroupedExpression); err != nil {
@@ -903,3 +1426,204 @@ func (p *LLParser) parseIfExpression() (*ast.IfExpression, error) {
 
 	return stmt, nil
 }
+
+// match-expression => "match" "(" expression ")" "{" [ match-arm *( "," match-arm ) [","] ] "}"
+// match-arm => match-pattern [ "if" expression ] "=>" expression
+func (p *LLParser) parseMatchExpression() (*ast.MatchExpression, error) {
+	var err error
+	expr := &ast.MatchExpression{}
+
+	expr.Match, _ = p.skipToken(token.Match, RuleMatchExpression)
+	if expr.LParen, err = p.skipTokenAndComment(token.LParen, RuleMatchExpression); err != nil {
+		return nil, err
+	}
+
+	if expr.Subject, err = p.parseExpression(PrecedenceLowest); err != nil {
+		return nil, err
+	}
+
+	if expr.RParen, err = p.skipTokenAndComment(token.RParen, RuleMatchExpression); err != nil {
+		return nil, err
+	}
+
+	if expr.LBrace, err = p.skipTokenAndComment(token.LBrace, RuleMatchExpression); err != nil {
+		return nil, err
+	}
+
+	for {
+		current, _ := p.currentSkipComment()
+		if current.Token == token.RBrace {
+			break
+		}
+
+		arm := &ast.MatchArm{}
+		if arm.Pattern, err = p.parseMatchPattern(); err != nil {
+			return nil, err
+		}
+
+		if arm.If, err = p.skipTokenAndComment(token.If, RuleMatchExpression); err == nil {
+			if arm.Guard, err = p.parseExpression(PrecedenceLowest); err != nil {
+				return nil, err
+			}
+		}
+
+		if arm.Arrow, err = p.skipTokenAndComment(token.Arrow, RuleMatchExpression); err != nil {
+			return nil, err
+		}
+
+		if arm.Value, err = p.parseExpression(PrecedenceLowest); err != nil {
+			return nil, err
+		}
+
+		expr.Arms = append(expr.Arms, arm)
+		if arm.Comma, err = p.skipTokenAndComment(token.Comma, RuleMatchExpression); err != nil {
+			break
+		}
+	}
+
+	if expr.RBrace, err = p.skipTokenAndComment(token.RBrace, RuleMatchExpression); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+// match-pattern
+// => literal-pattern / identifier / array-match-pattern / hash-match-pattern
+// literal-pattern => [ "-" ] ( integer-literal / float-literal )
+// => string-literal / boolean-literal / null-literal
+func (p *LLParser) parseMatchPattern() (ast.Pattern, error) {
+	var pattern ast.Pattern
+	var err error
+
+	current, _ := p.currentSkipComment()
+	switch current.Token {
+	case token.Integer, token.Float, token.String, token.True, token.False, token.Null:
+		value, _ := p.parseLiteral()
+		pattern = &ast.LiteralPattern{Value: value}
+
+	case token.Minus:
+		p.nextToken()
+		number, _ := p.currentSkipComment()
+		if number.Token != token.Integer && number.Token != token.Float {
+			expecteds := []token.Token{token.Integer, token.Float}
+			return nil, p.unexpectedError(RuleMatchPattern, expecteds)
+		}
+
+		value, _ := p.parseLiteral()
+		expr := &ast.PrefixExpression{
+			Prefix:  current,
+			Operand: value,
+		}
+		pattern = &ast.LiteralPattern{Value: expr}
+
+	case token.Identifier:
+		name, _ := p.parseIdentifier()
+		pattern = &ast.BindingPattern{Name: name}
+
+	case token.LBracket:
+		pattern, err = p.parseArrayMatchPattern()
+
+	case token.LBrace:
+		pattern, err = p.parseHashMatchPattern()
+
+	default:
+		expecteds := []token.Token{
+			token.Integer, token.Float, token.String, token.True, token.False, token.Null,
+			token.Minus, token.Identifier, token.LBracket, token.LBrace,
+		}
+		err = p.unexpectedError(RuleMatchPattern, expecteds)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pattern, nil
+}
+
+// array-match-pattern => "[" [ match-pattern *( "," match-pattern ) [","] ] [ "..." identifier ] "]"
+func (p *LLParser) parseArrayMatchPattern() (*ast.ArrayMatchPattern, error) {
+	var err error
+	pattern := &ast.ArrayMatchPattern{}
+
+	pattern.LBracket, _ = p.skipTokenAndComment(token.LBracket, RuleMatchPattern)
+
+	current, _ := p.currentSkipComment()
+	for current.Token != token.RBracket && current.Token != token.Ellipsis {
+		elem, err := p.parseMatchPattern()
+		if err != nil {
+			return nil, err
+		}
+
+		pattern.Elements = append(pattern.Elements, elem)
+		if _, err := p.skipTokenAndComment(token.Comma, RuleMatchPattern); err != nil {
+			break
+		}
+
+		current, _ = p.currentSkipComment()
+	}
+
+	if pattern.Ellipsis, err = p.skipTokenAndComment(token.Ellipsis, RuleMatchPattern); err == nil {
+		if pattern.Rest, err = p.parseIdentifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	if pattern.RBracket, err = p.skipTokenAndComment(token.RBracket, RuleMatchPattern); err != nil {
+		return nil, err
+	}
+
+	return pattern, nil
+}
+
+// hash-match-pattern => "{" [ hash-match-pair *( "," hash-match-pair ) [","] ] "}"
+// hash-match-pair => identifier [ ":" match-pattern ] / literal ":" match-pattern
+func (p *LLParser) parseHashMatchPattern() (*ast.HashMatchPattern, error) {
+	var err error
+	pattern := &ast.HashMatchPattern{}
+
+	pattern.LBrace, _ = p.skipTokenAndComment(token.LBrace, RuleMatchPattern)
+
+	current, _ := p.currentSkipComment()
+	for current.Token != token.RBrace {
+		pair := &ast.HashMatchPair{}
+		switch current.Token {
+		case token.Identifier:
+			name, _ := p.parseIdentifier()
+			pair.Key = name
+			pair.Value = &ast.BindingPattern{Name: name}
+
+		case token.Integer, token.String, token.True, token.False:
+			pair.Key, _ = p.parseLiteral()
+
+		default:
+			expecteds := []token.Token{
+				token.Identifier, token.Integer, token.String, token.True, token.False,
+			}
+			return nil, p.unexpectedError(RuleMatchPattern, expecteds)
+		}
+
+		if pair.Colon, err = p.skipTokenAndComment(token.Colon, RuleMatchPattern); err == nil {
+			if pair.Value, err = p.parseMatchPattern(); err != nil {
+				return nil, err
+			}
+
+		} else if _, ok := pair.Key.(*ast.Identifier); !ok {
+			return nil, err
+		}
+
+		pattern.Pairs = append(pattern.Pairs, pair)
+		if pair.Comma, err = p.skipTokenAndComment(token.Comma, RuleMatchPattern); err != nil {
+			break
+		}
+
+		current, _ = p.currentSkipComment()
+	}
+
+	if pattern.RBrace, err = p.skipTokenAndComment(token.RBrace, RuleMatchPattern); err != nil {
+		return nil, err
+	}
+
+	return pattern, nil
+}
@@ -185,6 +185,258 @@ func TestParseLetStatementError(t *testing.T) {
 	runParserErrorTestCase(t, tests)
 }
 
+func TestParseDestructuringLetStatement(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`let [a, b, ...rest] = arr;`,
+			program(
+				letPattern(
+					arrayPattern([]string{"a", "b"}, "rest"),
+					exprList(id("arr")),
+				),
+			),
+		},
+		{
+			`let [a, b,] = [1, 2];`,
+			program(
+				letPattern(
+					arrayPattern([]string{"a", "b"}, ""),
+					exprList(array(l(1), l(2))),
+				),
+			),
+		},
+		{
+			`let [...all] = arr;`,
+			program(
+				letPattern(
+					arrayPattern(nil, "all"),
+					exprList(id("arr")),
+				),
+			),
+		},
+		{
+			`let {name, age: years} = person;`,
+			program(
+				letPattern(
+					hashPattern("name", "name", "age", "years"),
+					exprList(id("person")),
+				),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+
+	// Canonical code of patterns.
+	code := `var {a, b: c} = h; let [x, ...y] = a;`
+	program, err := testLLParseCode(code)
+	if err != nil {
+		t.Fatalf("Parse code error:\n%s", err)
+	}
+
+	expected := "var {a, b: c} = h;\nlet [x, ...y] = a;"
+	if program.CanonicalCode() != expected {
+		t.Errorf("wrong canonical code, got:\n%s\nexpected:\n%s",
+			program.CanonicalCode(), expected)
+	}
+}
+
+func TestParseDestructuringLetStatementError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`let [a, ...b, c] = x;`,
+				"            ^",
+				"            expect token RBRACKET(']') IN array pattern, but got COMMA(,)",
+				"  at testcase:1:13",
+			},
+		},
+		{
+			[]string{
+				`let {a: 1} = x;`,
+				"        ^",
+				"        expect token IDENTIFIER IN identifier, but got INTEGER",
+				"  at testcase:1:9",
+			},
+		},
+		{
+			[]string{
+				`let [a b] = x;`,
+				"       ^",
+				"       expect token RBRACKET(']') IN array pattern, but got IDENTIFIER",
+				"  at testcase:1:8",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
+func TestParseVarStatement(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`var answer = 42;`,
+			program(
+				varStmt(
+					idList("answer"),
+					exprList(
+						l(42),
+					),
+				),
+			),
+		},
+		{
+			`var a, b = 1, 2; let c = a;`,
+			program(
+				varStmt(
+					idList("a", "b"),
+					exprList(
+						l(1),
+						l(2),
+					),
+				),
+				let(
+					idList("c"),
+					exprList(
+						id("a"),
+					),
+				),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+}
+
+func TestParseVarStatementError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`var answer;`,
+				"          ^",
+				"          expect token ASSIGN(=) IN var statement, but got SEMICOLON(;)",
+				"  at testcase:1:11",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
+func TestParseAssignExpression(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`a = 1;`,
+			program(
+				expr(
+					assign(id("a"), l(1)),
+				),
+			),
+		},
+		{
+			`a = b = c + 1;`,
+			program(
+				expr(
+					assign(
+						id("a"),
+						assign(
+							id("b"),
+							infix("+", id("c"), l(1)),
+						),
+					),
+				),
+			),
+		},
+		{
+			`a[0] = 1, h.key = 2;`,
+			program(
+				expr(
+					assign(
+						&ast.IndexExpression{
+							Base:  id("a"),
+							Index: l(0),
+						},
+						l(1),
+					),
+					assign(
+						&ast.IndexExpression{
+							Base:  id("h"),
+							Index: id("key"),
+						},
+						l(2),
+					),
+				),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+}
+
+func TestParseSliceExpression(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`a[1:2], a[:-1], a[1:], a[:]`,
+			program(
+				expr(
+					slice(id("a"), l(1), l(2)),
+					slice(id("a"), nil, prefix("-", l(1))),
+					slice(id("a"), l(1), nil),
+					slice(id("a"), nil, nil),
+				),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+}
+
+func TestParseSliceExpressionError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`a[1:2:3]`,
+				"     ^",
+				"     expect token RBRACKET(']') IN slice expression, but got COLON(:)",
+				"  at testcase:1:6",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
+func TestParseAssignExpressionError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`1 = 2;`,
+				"^",
+				"1 can not be assigned",
+				"  at testcase:1:1",
+			},
+		},
+		{
+			[]string{
+				`a + b = 2;`,
+				"^ ^ ^",
+				"(a + b) can not be assigned",
+				"  at testcase:1:1",
+			},
+		},
+		{
+			[]string{
+				`f() = 2;`,
+				"^^^",
+				"f() can not be assigned",
+				"  at testcase:1:1",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
 func TestParseReturnStatement(t *testing.T) {
 	tests := []parserTestCase{
 		{
@@ -230,6 +482,227 @@ func TestParseReturnStatement(t *testing.T) {
 	runParserTestCase(t, tests)
 }
 
+func TestParseImportStatement(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`import "lib/math"`,
+			program(
+				importStmt("", `"lib/math"`),
+			),
+		},
+		{
+			`import "lib/math";`,
+			program(
+				importStmt("", `"lib/math"`),
+			),
+		},
+		{
+			`import m "lib/math"; m`,
+			program(
+				importStmt("m", `"lib/math"`),
+				expr(id("m")),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+}
+
+func TestParseImportStatementError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`import lib.math;`,
+				"          ^",
+				"          expect token STRING IN import statement, but got PERIOD(.)",
+				"  at testcase:1:11",
+			},
+		},
+		{
+			[]string{
+				`import 42;`,
+				"       ^^",
+				"       expect token STRING IN import statement, but got INTEGER",
+				"  at testcase:1:8",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
+func TestParseThrowStatement(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`throw "error"`,
+			program(
+				throw(l(`"error"`)),
+			),
+		},
+		{
+			`throw {"code": 42};`,
+			program(
+				throw(hash(pair(l(`"code"`), l(42)))),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+}
+
+func TestParseTryStatement(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			makeMultilines(
+				`try {`,
+				`	throw 1;`,
+				`} catch (e) {`,
+				`	e`,
+				`}`,
+			),
+			program(
+				try(
+					block(throw(l(1))),
+					"e", block(expr(id("e"))),
+					nil,
+				),
+			),
+		},
+		{
+			`try { 1 } finally { 2 }`,
+			program(
+				try(
+					block(expr(l(1))),
+					"", nil,
+					block(expr(l(2))),
+				),
+			),
+		},
+		{
+			makeMultilines(
+				`try { 1 }`,
+				`// comment`,
+				`catch (err) { 2 }`,
+				`finally { 3 }`,
+				`4`,
+			),
+			program(
+				try(
+					block(expr(l(1))),
+					"err", block(expr(l(2))),
+					block(expr(l(3))),
+				),
+				expr(l(4)),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+}
+
+func TestParseTryStatementError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`try { 1 } 2`,
+				"          ^",
+				"          expect token CATCH or FINALLY IN try statement, but got INTEGER",
+				"  at testcase:1:11",
+			},
+		},
+		{
+			[]string{
+				`try { 1 } catch { 2 }`,
+				"                ^",
+				"                expect token LPAREN('(') IN try statement, but got LBRACE('{')",
+				"  at testcase:1:17",
+			},
+		},
+		{
+			[]string{
+				`try 1 catch (e) { 2 }`,
+				"    ^",
+				"    expect token LBRACE('{') IN try statement, but got INTEGER",
+				"  at testcase:1:5",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
+func TestParseLoopStatement(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			makeMultilines(
+				`while (a < 10) {`,
+				`	a = a + 1;`,
+				`}`,
+			),
+			program(
+				while(
+					infix("<", id("a"), l(10)),
+					block(expr(assign(id("a"), infix("+", id("a"), l(1))))),
+				),
+			),
+		},
+		{
+			makeMultilines(
+				`for (x in [1, 2, 3]) {`,
+				`	if (x == 2) { continue }`,
+				`	break;`,
+				`}`,
+			),
+			program(
+				forIn(
+					"x", array(l(1), l(2), l(3)),
+					block(
+						expr(ifexp(
+							infix("==", id("x"), l(2)),
+							block(cont()),
+							nil,
+						)),
+						brk(),
+					),
+				),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+}
+
+func TestParseLoopStatementError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`while a { 1 }`,
+				"      ^",
+				"      expect token LPAREN('(') IN while statement, but got IDENTIFIER",
+				"  at testcase:1:7",
+			},
+		},
+		{
+			[]string{
+				`for (x of a) { 1 }`,
+				"       ^^",
+				"       expect token IN IN for statement, but got IDENTIFIER",
+				"  at testcase:1:8",
+			},
+		},
+		{
+			[]string{
+				`while (true) 1`,
+				"             ^",
+				"             expect token LBRACE('{') IN while statement, but got INTEGER",
+				"  at testcase:1:14",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
 func TestParseExpressionList(t *testing.T) {
 	tests := []parserTestCase{
 		{
@@ -663,6 +1136,101 @@ func TestParseFunctionLiteralError(t *testing.T) {
 	runParserErrorTestCase(t, tests)
 }
 
+func TestParseVariadicFunction(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`fn(...args) { args }`,
+			program(
+				expr(
+					variadic(
+						idList(),
+						"args",
+						block(
+							expr(id("args")),
+						),
+					),
+				),
+			),
+		},
+		{
+			`fn(a, b, ...rest) { rest }`,
+			program(
+				expr(
+					variadic(
+						idList("a", "b"),
+						"rest",
+						block(
+							expr(id("rest")),
+						),
+					),
+				),
+			),
+		},
+		{
+			`f(1, ...a, ...[2, 3])`,
+			program(
+				expr(
+					&ast.CallExpression{
+						Base: id("f"),
+						Args: exprList(
+							l(1),
+							spread(id("a")),
+							spread(array(l(2), l(3))),
+						),
+					},
+				),
+			),
+		},
+		{
+			`fn(...args)`,
+			program(
+				expr(
+					&ast.CallExpression{
+						Token: punct(token.Fn),
+						Args: exprList(
+							spread(id("args")),
+						),
+						Recursion: true,
+					},
+				),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+}
+
+func TestParseVariadicFunctionError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`fn(...a, b) { a }`,
+				"            ^",
+				"            recursion function call MUST NOT follow by a block statement",
+				"  at testcase:1:13",
+			},
+		},
+		{
+			[]string{
+				`fn(a, ...[b]) { a }`,
+				"              ^",
+				"              recursion function call MUST NOT follow by a block statement",
+				"  at testcase:1:15",
+			},
+		},
+		{
+			[]string{
+				`let a = ...b;`,
+				"        ^^^",
+				"        expect token IDENTIFIER IN expression list, but got ELLIPSIS(...)",
+				"  at testcase:1:9",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
 func TestParseIfExpression(t *testing.T) {
 	tests := []parserTestCase{
 		{
@@ -791,6 +1359,227 @@ func TestParseIfExpression(t *testing.T) {
 	runParserTestCase(t, tests)
 }
 
+func TestParseMatchExpression(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`match (x) { 1 => "one", -2.5 => "neg", _ => null };`,
+			program(
+				expr(
+					match(id("x"),
+						arm(litPattern(1), nil, l(`"one"`)),
+						arm(&ast.LiteralPattern{Value: prefix("-", float("2.5"))}, nil, l(`"neg"`)),
+						arm(bindPattern("_"), nil, l(nil)),
+					),
+				),
+			),
+		},
+		{
+			`match (p) { [0, y] => y, [x, ...rest] if x > 0 => rest, };`,
+			program(
+				expr(
+					match(id("p"),
+						arm(arrayMatch("", litPattern(0), bindPattern("y")), nil, id("y")),
+						arm(arrayMatch("rest", bindPattern("x")), infix(">", id("x"), l(0)), id("rest")),
+					),
+				),
+			),
+		},
+		{
+			`let area = match (s) { {kind: "circle", r} => r * r, {"w": w, 1: [h]} => w * h };`,
+			program(
+				let(
+					idList("area"),
+					exprList(
+						match(id("s"),
+							arm(
+								hashMatch(
+									matchPair(id("kind"), litPattern(`"circle"`)),
+									matchPair(id("r"), nil),
+								),
+								nil,
+								infix("*", id("r"), id("r")),
+							),
+							arm(
+								hashMatch(
+									matchPair(l(`"w"`), bindPattern("w")),
+									matchPair(l(1), arrayMatch("", bindPattern("h"))),
+								),
+								nil,
+								infix("*", id("w"), id("h")),
+							),
+						),
+					),
+				),
+			),
+		},
+		{
+			`match (f()) {};`,
+			program(
+				expr(
+					match(&ast.CallExpression{Base: id("f"), Args: exprList()}),
+				),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+
+	code := `match (v) { [a, ...b] if a => b, {k: -1, n} => n, _ => 0 };`
+	program, err := testLLParseCode(code)
+	if err != nil {
+		t.Fatalf("Parse code error:\n%s", err)
+	}
+
+	expected := "match ( v ) { [a, ...b] if a => b, {k: (- 1), n} => n, _ => 0 };"
+	if program.CanonicalCode() != expected {
+		t.Errorf("wrong canonical code, got:\n%s\nexpected:\n%s",
+			program.CanonicalCode(), expected)
+	}
+}
+
+func TestParseMatchExpressionError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`match (x) { 1 -> 2 };`,
+				"              ^",
+				"              expect token ARROW(=>) IN match expression, but got MINUS(-)",
+				"  at testcase:1:15",
+			},
+		},
+		{
+			[]string{
+				`match (x) { a + 1 => 2 };`,
+				"              ^",
+				"              expect token ARROW(=>) IN match expression, but got PLUS(+)",
+				"  at testcase:1:15",
+			},
+		},
+		{
+			[]string{
+				`match (x) { -a => 2 };`,
+				"             ^",
+				"             unexpected token IDENTIFIER IN match pattern",
+				"  at testcase:1:14",
+			},
+		},
+		{
+			[]string{
+				`match (x) { {"k"} => 2 };`,
+				"                ^",
+				"                expect token COLON(:) IN match pattern, but got RBRACE('}')",
+				"  at testcase:1:17",
+			},
+		},
+		{
+			[]string{
+				`match x { _ => 2 };`,
+				"      ^",
+				"      expect token LPAREN('(') IN match expression, but got IDENTIFIER",
+				"  at testcase:1:7",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
+func TestParseInterpolatedString(t *testing.T) {
+	tests := []parserTestCase{
+		{
+			`let s = "hello ${name}, you are ${age + 1}";`,
+			program(
+				let(
+					idList("s"),
+					exprList(
+						interpolate(
+							"hello ", id("name"), ", you are ", infix("+", id("age"), l(1)),
+						),
+					),
+				),
+			),
+		},
+		{
+			`"${a}${"b${c}"}\${d}";`,
+			program(
+				expr(
+					interpolate(
+						id("a"), interpolate("b", id("c")), `\${d}`,
+					),
+				),
+			),
+		},
+		{
+			`"no \${interpolation}";`,
+			program(
+				expr(
+					l(`"no \${interpolation}"`),
+				),
+			),
+		},
+	}
+
+	runParserTestCase(t, tests)
+
+	code := "let s = 1;\n\t\"x\\n${ f(s) } ${s}\";"
+	program, err := testLLParseCode(code)
+	if err != nil {
+		t.Fatalf("Parse code error:\n%s", err)
+	}
+
+	expected := `let s = 1;` + "\n" + `"x\n${f(s)} ${s}";`
+	if program.CanonicalCode() != expected {
+		t.Errorf("wrong canonical code, got:\n%s\nexpected:\n%s",
+			program.CanonicalCode(), expected)
+	}
+
+	s := program.Statements[1].(*ast.ExpressionStatement).Expressions.Expressions[0].Expression.(*ast.InterpolatedString)
+	positions := [][2]int{
+		{2, 2},  // string
+		{2, 2},  // "x\n"
+		{2, 9},  // f(s)
+		{2, 2},  // " "
+		{2, 18}, // s
+	}
+
+	contexts := []*token.Context{s.GetContext()}
+	for _, part := range s.Parts {
+		contexts = append(contexts, part.GetContext())
+	}
+
+	for i, ctx := range contexts {
+		elem := ctx.Tokens[0]
+		line, column := elem.LineNo(), elem.ColumnStart()
+		if line != positions[i][0] || column != positions[i][1] {
+			t.Errorf("part %d at wrong position %d:%d, expected %d:%d",
+				i, line, column, positions[i][0], positions[i][1])
+		}
+	}
+}
+
+func TestParseInterpolatedStringError(t *testing.T) {
+	tests := []parserErrorTestCase{
+		{
+			[]string{
+				`let s = "x ${a +} y";`,
+				"                ^",
+				"                unexpected token EOF IN EXPRESSION",
+				"  at testcase:1:17",
+			},
+		},
+		{
+			[]string{
+				`let s = "x ${a b} y";`,
+				"               ^",
+				"               expect token EOF IN string interpolation, but got IDENTIFIER",
+				"  at testcase:1:16",
+			},
+		},
+	}
+
+	runParserErrorTestCase(t, tests)
+}
+
 func TestOperatorPrecedence(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -911,6 +1700,10 @@ func TestOperatorPrecedence(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])));",
 		},
+		{
+			"a[1:n - 1] + s[:2][0] + t[i:]",
+			"(((a[1:(n - 1)]) + ((s[:2])[0])) + (t[i:]));",
+		},
 		{
 			"a + b::c(d + e) * f",
 			"(a + (b::c((d + e)) * f));",
@@ -1053,6 +1846,30 @@ func TestCodeWithComments(t *testing.T) {
 				),
 			),
 		},
+		{
+			`
+			/* A block comment
+			   /* nested comment */
+			 */
+			let a = /* inline */ 3 + /*
+			*/ 5;
+			let s = ` + "`raw ${a} \\n\n" + `"line"` + "`" + `;
+			`,
+			program(
+				let(
+					idList("a"),
+					exprList(
+						infix("+", l(3), l(5)),
+					),
+				),
+				let(
+					idList("s"),
+					exprList(
+						l("`raw ${a} \\n\n\"line\"`"),
+					),
+				),
+			),
+		},
 	}
 
 	runParserTestCase(t, tests)
@@ -7,6 +7,7 @@ import (
 const (
 	_ int = iota
 	PrecedenceLowest
+	PrecedenceAssign                // =
 	PrecedenceLogicalOR             // ||
 	PrecedenceLogicalAND            // &&
 	PrecedenceComparisonEqual       // == !=
@@ -27,6 +28,7 @@ const (
 )
 
 var precedenceMap = map[token.Token]int{
+	token.Assign:    PrecedenceAssign,
 	token.AND:       PrecedenceLogicalAND,
 	token.OR:        PrecedenceLogicalOR,
 	token.EQ:        PrecedenceComparisonEqual,
@@ -25,7 +25,7 @@ func TestGetPrecedence(t *testing.T) {
 		{token.GE, PrecedenceComparisonLessGreater},
 		{token.AND, PrecedenceLogicalAND},
 		{token.OR, PrecedenceLogicalOR},
-		{token.Assign, 0},
+		{token.Assign, PrecedenceAssign},
 		{token.Period, PrecedenceIndex},
 		{token.Comma, 0},
 		{token.Colon, 0},
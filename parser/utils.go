@@ -88,6 +88,50 @@ func let(identifers *ast.IdentifierList, expressions *ast.ExpressionList) *ast.L
 	return stmt
 }
 
+func varStmt(identifers *ast.IdentifierList, expressions *ast.ExpressionList) *ast.LetStatement {
+	stmt := let(identifers, expressions)
+	stmt.Let = &token.TokenContext{
+		Token: token.Var,
+	}
+
+	return stmt
+}
+
+func letPattern(pattern ast.Pattern, expressions *ast.ExpressionList) *ast.LetStatement {
+	stmt := &ast.LetStatement{
+		Pattern:     pattern,
+		Expressions: expressions,
+	}
+
+	return stmt
+}
+
+func arrayPattern(names []string, rest string) *ast.ArrayPattern {
+	pattern := &ast.ArrayPattern{
+		Elements: idList(names...),
+	}
+
+	if rest != "" {
+		pattern.Rest = id(rest)
+	}
+
+	return pattern
+}
+
+// hashPattern makes a hash pattern from pairs of key and variable name.
+func hashPattern(pairs ...string) *ast.HashPattern {
+	pattern := &ast.HashPattern{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		pair := &ast.HashPatternPair{
+			Key:   id(pairs[i]),
+			Value: id(pairs[i+1]),
+		}
+		pattern.Pairs = append(pattern.Pairs, pair)
+	}
+
+	return pattern
+}
+
 func ret(expressions ...ast.Expression) *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{
 		Expressions: exprList(expressions...),
@@ -96,6 +140,71 @@ func ret(expressions ...ast.Expression) *ast.ReturnStatement {
 	return stmt
 }
 
+func importStmt(name string, path string) *ast.ImportStatement {
+	stmt := &ast.ImportStatement{
+		Target: &token.TokenContext{
+			Token:   token.String,
+			Content: path,
+		},
+		Path: ConvertString(path),
+	}
+
+	if name != "" {
+		stmt.Name = id(name)
+	}
+
+	return stmt
+}
+
+func throw(expression ast.Expression) *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{
+		Expression: expression,
+	}
+
+	return stmt
+}
+
+func try(body *ast.BlockStatement, name string, catch *ast.BlockStatement, finally *ast.BlockStatement) *ast.TryStatement {
+	stmt := &ast.TryStatement{
+		Body:        body,
+		CatchBody:   catch,
+		FinallyBody: finally,
+	}
+
+	if catch != nil {
+		stmt.Variable = id(name)
+	}
+
+	return stmt
+}
+
+func while(condition ast.Expression, body *ast.BlockStatement) *ast.WhileStatement {
+	stmt := &ast.WhileStatement{
+		Condition: condition,
+		Body:      body,
+	}
+
+	return stmt
+}
+
+func forIn(name string, iterable ast.Expression, body *ast.BlockStatement) *ast.ForStatement {
+	stmt := &ast.ForStatement{
+		Variable: id(name),
+		Iterable: iterable,
+		Body:     body,
+	}
+
+	return stmt
+}
+
+func brk() *ast.BreakStatement {
+	return &ast.BreakStatement{}
+}
+
+func cont() *ast.ContinueStatement {
+	return &ast.ContinueStatement{}
+}
+
 func id(name string) *ast.Identifier {
 	id := &ast.Identifier{
 		Value: name,
@@ -154,6 +263,25 @@ func infix(operator string, left ast.Expression, right ast.Expression) *ast.Infi
 	return expr
 }
 
+func assign(target ast.Expression, value ast.Expression) *ast.AssignExpression {
+	expr := &ast.AssignExpression{
+		Target: target,
+		Value:  value,
+	}
+
+	return expr
+}
+
+func slice(base ast.Expression, low ast.Expression, high ast.Expression) *ast.SliceExpression {
+	expr := &ast.SliceExpression{
+		Base: base,
+		Low:  low,
+		High: high,
+	}
+
+	return expr
+}
+
 func array(elements ...ast.Expression) *ast.ArrayLiteral {
 	expr := &ast.ArrayLiteral{
 		Expressions: exprList(elements...),
@@ -213,3 +341,95 @@ func fn(args *ast.IdentifierList, body *ast.BlockStatement) *ast.FunctionLiteral
 
 	return expr
 }
+
+func variadic(args *ast.IdentifierList, rest string, body *ast.BlockStatement) *ast.FunctionLiteral {
+	expr := fn(args, body)
+	expr.Rest = id(rest)
+	return expr
+}
+
+func spread(expression ast.Expression) *ast.SpreadExpression {
+	expr := &ast.SpreadExpression{
+		Expression: expression,
+	}
+
+	return expr
+}
+
+func match(subject ast.Expression, arms ...*ast.MatchArm) *ast.MatchExpression {
+	expr := &ast.MatchExpression{
+		Subject: subject,
+		Arms:    arms,
+	}
+
+	return expr
+}
+
+func arm(pattern ast.Pattern, guard ast.Expression, value ast.Expression) *ast.MatchArm {
+	arm := &ast.MatchArm{
+		Pattern: pattern,
+		Guard:   guard,
+		Value:   value,
+	}
+
+	return arm
+}
+
+func litPattern(v interface{}) *ast.LiteralPattern {
+	return &ast.LiteralPattern{Value: l(v)}
+}
+
+func bindPattern(name string) *ast.BindingPattern {
+	return &ast.BindingPattern{Name: id(name)}
+}
+
+func arrayMatch(rest string, elements ...ast.Pattern) *ast.ArrayMatchPattern {
+	pattern := &ast.ArrayMatchPattern{
+		Elements: elements,
+	}
+
+	if rest != "" {
+		pattern.Rest = id(rest)
+	}
+
+	return pattern
+}
+
+// hashMatch makes a hash match pattern, pair with nil value is shorthand of
+// binding the key name.
+func hashMatch(pairs ...*ast.HashMatchPair) *ast.HashMatchPattern {
+	return &ast.HashMatchPattern{Pairs: pairs}
+}
+
+func matchPair(key ast.Expression, value ast.Pattern) *ast.HashMatchPair {
+	pair := &ast.HashMatchPair{
+		Key:   key,
+		Value: value,
+	}
+
+	if value == nil {
+		pair.Value = &ast.BindingPattern{Name: key.(*ast.Identifier)}
+	}
+
+	return pair
+}
+
+// interpolate makes an interpolated string, string parts are pieces of
+// characters without quotes.
+func interpolate(parts ...interface{}) *ast.InterpolatedString {
+	s := &ast.InterpolatedString{}
+	for _, part := range parts {
+		switch v := part.(type) {
+		case string:
+			s.Parts = append(s.Parts, &ast.StringLiteral{
+				Value:   unescapeString(v),
+				Content: v,
+			})
+
+		case ast.Expression:
+			s.Parts = append(s.Parts, v)
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,112 @@
+package std
+
+import (
+	"fmt"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+func init() {
+	Register(&Module{
+		Name: "std/arrays",
+		Functions: map[string]Function{
+			"first":  arraysFirst,
+			"rest":   arraysRest,
+			"last":   arraysLast,
+			"push":   arraysPush,
+			"map":    arraysMethod("map", 2),
+			"filter": arraysMethod("filter", 2),
+			"reduce": arraysMethod("reduce", 3),
+		},
+	})
+}
+
+// arraysMethod makes a function calling native method name of array, with the
+// array as the first argument, like `map(a, f)` is `a::map(f)`.
+func arraysMethod(name string, n int) Function {
+	return func(c object.Caller, args []object.Object) ([]object.Object, error) {
+		if err := object.CheckArguments(name, args, n); err != nil {
+			return nil, err
+		}
+
+		a, err := arrayArgument(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return callMethod(c, a, name, args[1:]...)
+	}
+}
+
+func arraysFirst(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("first", args, 1); err != nil {
+		return nil, err
+	}
+
+	a, err := arrayArgument("first", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(a.Elements) == 0 {
+		return object.Values(object.NewNull()), nil
+	}
+
+	return object.Values(a.Elements[0]), nil
+}
+
+// arraysRest returns a new array of all elements but the first one, or null if
+// the array is empty.
+func arraysRest(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("rest", args, 1); err != nil {
+		return nil, err
+	}
+
+	a, err := arrayArgument("rest", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(a.Elements) == 0 {
+		return object.Values(object.NewNull()), nil
+	}
+
+	elements := make([]object.Object, len(a.Elements)-1)
+	copy(elements, a.Elements[1:])
+	return object.Values(object.NewArray(elements)), nil
+}
+
+func arraysLast(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("last", args, 1); err != nil {
+		return nil, err
+	}
+
+	a, err := arrayArgument("last", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(a.Elements) == 0 {
+		return object.Values(object.NewNull()), nil
+	}
+
+	return object.Values(a.Elements[len(a.Elements)-1]), nil
+}
+
+// arraysPush returns a new array with values appended, the array itself is not
+// changed.
+func arraysPush(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("push requires at least 1 argument")
+	}
+
+	a, err := arrayArgument("push", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]object.Object, 0, len(a.Elements)+len(args)-1)
+	elements = append(elements, a.Elements...)
+	elements = append(elements, args[1:]...)
+	return object.Values(object.NewArray(elements)), nil
+}
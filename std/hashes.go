@@ -0,0 +1,140 @@
+package std
+
+import (
+	"github.com/flily/macaque-lang/object"
+)
+
+func init() {
+	Register(&Module{
+		Name: "std/hashes",
+		Functions: map[string]Function{
+			"keys":   hashesMethod("keys", 1),
+			"values": hashesMethod("values", 1),
+			"has":    hashesMethod("has", 2),
+			"merge":  hashesMerge,
+			"map":    hashesMap,
+			"filter": hashesFilter,
+			"reduce": hashesReduce,
+		},
+	})
+}
+
+// hashesMethod makes a function calling native method name of hash, with the
+// hash as the first argument, like `keys(h)` is `h::keys()`.
+func hashesMethod(name string, n int) Function {
+	return func(c object.Caller, args []object.Object) ([]object.Object, error) {
+		if err := object.CheckArguments(name, args, n); err != nil {
+			return nil, err
+		}
+
+		h, err := hashArgument(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return callMethod(c, h, name, args[1:]...)
+	}
+}
+
+// hashesMerge returns a new hash with pairs of all hashes. Value of a key in
+// later hash replaces the earlier one, while the key keeps its first position.
+func hashesMerge(c object.Caller, args []object.Object) ([]object.Object, error) {
+	pairs := make([]object.HashPair, 0)
+	index := make(map[interface{}]int)
+	for i := range args {
+		h, err := hashArgument("merge", args, i)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range h.Elements {
+			key := e.Key.HashKey()
+			if j, ok := index[key]; ok {
+				pairs[j].Value = e.Value
+				continue
+			}
+
+			index[key] = len(pairs)
+			pairs = append(pairs, e)
+		}
+	}
+
+	return object.Values(object.NewHash(pairs)), nil
+}
+
+// hashesMap returns a new hash with the same keys, and values returned by f,
+// which is called with key and value of each pair.
+func hashesMap(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("map", args, 2); err != nil {
+		return nil, err
+	}
+
+	h, err := hashArgument("map", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([]object.HashPair, len(h.Elements))
+	for i, e := range h.Elements {
+		v, err := object.CallOne(c, args[1], e.Key, e.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs[i] = object.HashPair{Key: e.Key, Value: v}
+	}
+
+	return object.Values(object.NewHash(pairs)), nil
+}
+
+// hashesFilter returns a new hash with pairs that f returns true, which is
+// called with key and value of each pair.
+func hashesFilter(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("filter", args, 2); err != nil {
+		return nil, err
+	}
+
+	h, err := hashArgument("filter", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([]object.HashPair, 0)
+	for _, e := range h.Elements {
+		v, err := object.CallOne(c, args[1], e.Key, e.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if object.IsTrue(v) {
+			pairs = append(pairs, e)
+		}
+	}
+
+	return object.Values(object.NewHash(pairs)), nil
+}
+
+// hashesReduce calls f with accumulated value, key and value of each pair, and
+// returns the last result. The initial accumulated value is the last argument.
+func hashesReduce(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("reduce", args, 3); err != nil {
+		return nil, err
+	}
+
+	h, err := hashArgument("reduce", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := args[2]
+	for _, e := range h.Elements {
+		v, err := object.CallOne(c, args[1], acc, e.Key, e.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		acc = v
+	}
+
+	return object.Values(acc), nil
+}
@@ -0,0 +1,133 @@
+package std
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+func init() {
+	Register(&Module{
+		Name: "std/math",
+		Functions: map[string]Function{
+			"abs":   mathAbs,
+			"min":   mathMin,
+			"max":   mathMax,
+			"floor": mathFloor,
+			"pow":   mathPow,
+			"sqrt":  mathSqrt,
+		},
+	})
+}
+
+func numberArgument(name string, args []object.Object, i int) (float64, error) {
+	switch v := args[i].(type) {
+	case *object.IntegerObject:
+		return float64(v.Value), nil
+
+	case *object.FloatObject:
+		return v.Value, nil
+	}
+
+	return 0, fmt.Errorf("%s requires a number as argument %d, but got %s", name, i+1, args[i].Type())
+}
+
+func mathAbs(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("abs", args, 1); err != nil {
+		return nil, err
+	}
+
+	if n, ok := args[0].(*object.IntegerObject); ok {
+		if n.Value < 0 {
+			return object.Values(object.NewInteger(-n.Value)), nil
+		}
+
+		return object.Values(n), nil
+	}
+
+	x, err := numberArgument("abs", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Values(object.NewFloat(math.Abs(x))), nil
+}
+
+// mathCompare makes min or max of numbers, which returns the number itself, so
+// it is an integer if the chosen one is an integer.
+func mathCompare(name string, better func(a float64, b float64) bool) Function {
+	return func(c object.Caller, args []object.Object) ([]object.Object, error) {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%s requires at least 1 argument", name)
+		}
+
+		result := 0
+		best, err := numberArgument(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 1; i < len(args); i++ {
+			x, err := numberArgument(name, args, i)
+			if err != nil {
+				return nil, err
+			}
+
+			if better(x, best) {
+				result, best = i, x
+			}
+		}
+
+		return object.Values(args[result]), nil
+	}
+}
+
+var (
+	mathMin = mathCompare("min", func(a float64, b float64) bool { return a < b })
+	mathMax = mathCompare("max", func(a float64, b float64) bool { return a > b })
+)
+
+func mathFloor(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("floor", args, 1); err != nil {
+		return nil, err
+	}
+
+	x, err := numberArgument("floor", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Values(object.NewInteger(int64(math.Floor(x)))), nil
+}
+
+func mathPow(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("pow", args, 2); err != nil {
+		return nil, err
+	}
+
+	x, err := numberArgument("pow", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := numberArgument("pow", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Values(object.NewFloat(math.Pow(x, y))), nil
+}
+
+func mathSqrt(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("sqrt", args, 1); err != nil {
+		return nil, err
+	}
+
+	x, err := numberArgument("sqrt", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Values(object.NewFloat(math.Sqrt(x))), nil
+}
@@ -0,0 +1,103 @@
+// Package std is the standard library of macaque, made of system modules whose
+// members are native functions, like `import "std/strings"`.
+package std
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+// Function is a function of standard library. It may call functions of
+// scripts back by c.
+type Function func(c object.Caller, args []object.Object) ([]object.Object, error)
+
+// Module is a system module, which is a hash of native functions in scripts.
+type Module struct {
+	Name      string // path to import, like "std/strings"
+	Functions map[string]Function
+}
+
+var modules = make(map[string]*Module)
+
+// Register adds system module m, an existing module with the same name is
+// replaced.
+func Register(m *Module) {
+	modules[m.Name] = m
+}
+
+// Lookup finds system module by the path to import.
+func Lookup(name string) (*Module, bool) {
+	m, ok := modules[name]
+	return m, ok
+}
+
+// Modules returns all system modules, sorted by names.
+func Modules() []*Module {
+	result := make([]*Module, 0, len(modules))
+	for _, m := range modules {
+		result = append(result, m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// Names returns names of functions in module, in sorted order.
+func (m *Module) Names() []string {
+	names := make([]string, 0, len(m.Functions))
+	for name := range m.Functions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// NativeName returns the name of function name in module, which is registered
+// as native function to compiler and VM. It is not a valid identifier, so it
+// does not conflict with native functions provided by host.
+func (m *Module) NativeName(name string) string {
+	return m.Name + "." + name
+}
+
+// callMethod calls native method name of self, as `self::name(args)`.
+func callMethod(c object.Caller, self object.Object, name string, args ...object.Object) ([]object.Object, error) {
+	m, ok := self.OnMethod(name)
+	if !ok {
+		return nil, fmt.Errorf("%s has no method %s", self.Type(), name)
+	}
+
+	return c.CallFunction(m, append(object.Values(self), args...)...)
+}
+
+func stringArgument(name string, args []object.Object, i int) (string, error) {
+	s, ok := args[i].(*object.StringObject)
+	if !ok {
+		return "", fmt.Errorf("%s requires a STRING as argument %d, but got %s", name, i+1, args[i].Type())
+	}
+
+	return s.Value, nil
+}
+
+func arrayArgument(name string, args []object.Object, i int) (*object.ArrayObject, error) {
+	a, ok := args[i].(*object.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("%s requires an ARRAY as argument %d, but got %s", name, i+1, args[i].Type())
+	}
+
+	return a, nil
+}
+
+func hashArgument(name string, args []object.Object, i int) (*object.HashObject, error) {
+	h, ok := args[i].(*object.HashObject)
+	if !ok {
+		return nil, fmt.Errorf("%s requires a HASH as argument %d, but got %s", name, i+1, args[i].Type())
+	}
+
+	return h, nil
+}
@@ -0,0 +1,84 @@
+package std
+
+import (
+	"testing"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+func TestModules(t *testing.T) {
+	names := make([]string, 0)
+	for _, m := range Modules() {
+		names = append(names, m.Name)
+	}
+
+	expecteds := []string{"std/arrays", "std/hashes", "std/math", "std/strings"}
+	if len(names) != len(expecteds) {
+		t.Fatalf("wrong modules: %v", names)
+	}
+
+	for i, name := range expecteds {
+		if names[i] != name {
+			t.Errorf("wrong module %d, expect %s, got %s", i, name, names[i])
+		}
+	}
+
+	m, ok := Lookup("std/math")
+	if !ok {
+		t.Fatalf("module std/math not found")
+	}
+
+	if got := m.Names(); len(got) != 6 || got[0] != "abs" || got[5] != "sqrt" {
+		t.Errorf("wrong names of std/math: %v", got)
+	}
+
+	if got := m.NativeName("abs"); got != "std/math.abs" {
+		t.Errorf("wrong native name: %s", got)
+	}
+
+	if _, ok := Lookup("std/none"); ok {
+		t.Errorf("module std/none must not be found")
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	tests := []struct {
+		module   string
+		name     string
+		args     []object.Object
+		expected object.Object
+	}{
+		{
+			"std/strings", "replace",
+			object.Values(object.NewString("aXa"), object.NewString("a"), object.NewString("b")),
+			object.NewString("bXb"),
+		},
+		{
+			"std/math", "min",
+			object.Values(object.NewInteger(2), object.NewFloat(-1.5)),
+			object.NewFloat(-1.5),
+		},
+		{
+			"std/arrays", "push",
+			object.Values(object.NewArray(nil), object.NewInteger(1)),
+			object.NewArray(object.Values(object.NewInteger(1))),
+		},
+		{
+			"std/hashes", "merge",
+			object.Values(),
+			object.NewHash(nil),
+		},
+	}
+
+	for _, c := range tests {
+		m, _ := Lookup(c.module)
+		result, err := m.Functions[c.name](nil, c.args)
+		if err != nil {
+			t.Fatalf("%s.%s got error: %s", c.module, c.name, err)
+		}
+
+		if len(result) != 1 || !result[0].EqualTo(c.expected) {
+			t.Errorf("%s.%s expect %s, got %v", c.module, c.name, c.expected.Inspect(), result)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package std
+
+import (
+	"strings"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+func init() {
+	Register(&Module{
+		Name: "std/strings",
+		Functions: map[string]Function{
+			"length":  stringsLength,
+			"split":   stringsSplit,
+			"join":    stringsJoin,
+			"trim":    stringsTrim,
+			"find":    stringsFind,
+			"replace": stringsReplace,
+			"upper":   stringsUpper,
+			"lower":   stringsLower,
+		},
+	})
+}
+
+// stringsUnary makes a function converting a string to another one by f.
+func stringsUnary(name string, f func(string) string) Function {
+	return func(c object.Caller, args []object.Object) ([]object.Object, error) {
+		if err := object.CheckArguments(name, args, 1); err != nil {
+			return nil, err
+		}
+
+		s, err := stringArgument(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return object.Values(object.NewString(f(s))), nil
+	}
+}
+
+var (
+	stringsTrim  = stringsUnary("trim", strings.TrimSpace)
+	stringsUpper = stringsUnary("upper", strings.ToUpper)
+	stringsLower = stringsUnary("lower", strings.ToLower)
+)
+
+func stringsLength(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("length", args, 1); err != nil {
+		return nil, err
+	}
+
+	s, err := stringArgument("length", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Values(object.NewInteger(int64(len(s)))), nil
+}
+
+func stringsSplit(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("split", args, 2); err != nil {
+		return nil, err
+	}
+
+	s, err := stringArgument("split", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sep, err := stringArgument("split", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(s, sep)
+	elements := make([]object.Object, len(parts))
+	for i, part := range parts {
+		elements[i] = object.NewString(part)
+	}
+
+	return object.Values(object.NewArray(elements)), nil
+}
+
+// stringsJoin joins elements of array with separator, elements which are not
+// strings are joined in their inspect forms.
+func stringsJoin(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("join", args, 2); err != nil {
+		return nil, err
+	}
+
+	a, err := arrayArgument("join", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sep, err := stringArgument("join", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, len(a.Elements))
+	for i, e := range a.Elements {
+		parts[i] = e.Inspect()
+	}
+
+	return object.Values(object.NewString(strings.Join(parts, sep))), nil
+}
+
+// stringsFind returns index of the first substring in string, or -1 if it is
+// not found.
+func stringsFind(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("find", args, 2); err != nil {
+		return nil, err
+	}
+
+	s, err := stringArgument("find", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := stringArgument("find", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Values(object.NewInteger(int64(strings.Index(s, sub)))), nil
+}
+
+func stringsReplace(c object.Caller, args []object.Object) ([]object.Object, error) {
+	if err := object.CheckArguments("replace", args, 3); err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, 3)
+	for i := range parts {
+		s, err := stringArgument("replace", args, i)
+		if err != nil {
+			return nil, err
+		}
+
+		parts[i] = s
+	}
+
+	return object.Values(object.NewString(strings.ReplaceAll(parts[0], parts[1], parts[2]))), nil
+}
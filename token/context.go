@@ -95,14 +95,29 @@ func (c *Context) makeLineTokenMap() [][]*TokenContext {
 	tokens := make(map[int][]*TokenContext)
 
 	for _, token := range c.Tokens {
-		lineInfo := token.Position.Line
-		lineNo := lineInfo.Line
-		if _, hasLine := tokens[lineNo]; !hasLine {
-			tokens[lineNo] = make([]*TokenContext, 0)
-			lines = append(lines, lineNo)
+		// Pieces of a multi-line token are highlighted in their own lines.
+		for piece := token.Position; piece != nil; piece = piece.Next {
+			if piece.Length == 0 && piece != token.Position {
+				continue
+			}
+
+			lineNo := piece.Line.Line
+			if _, hasLine := tokens[lineNo]; !hasLine {
+				tokens[lineNo] = make([]*TokenContext, 0)
+				lines = append(lines, lineNo)
+			}
+
+			elem := token
+			if piece != token.Position {
+				elem = &TokenContext{
+					Token:    token.Token,
+					Position: piece,
+					Content:  piece.Content,
+				}
+			}
+
+			tokens[lineNo] = append(tokens[lineNo], elem)
 		}
-
-		tokens[lineNo] = append(tokens[lineNo], token)
 	}
 
 	result := make([][]*TokenContext, 0, len(lines))
@@ -20,11 +20,14 @@ type LineInfo struct {
 
 // TokenInfo represents a token in source file, it can be a lexical token parsed
 // by lexer or parser, or an invalid token which is not accepted by lexer.
+// A token across multiple lines is split into pieces in each line, and Next is
+// the piece in the next line.
 type TokenInfo struct {
 	Column  int
 	Length  int
 	Content string
 	Line    *LineInfo
+	Next    *TokenInfo
 }
 
 func NewFileInfo(filename string) *FileInfo {
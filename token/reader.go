@@ -1,5 +1,9 @@
 package token
 
+import (
+	"strings"
+)
+
 type FileReader struct {
 	FileInfo *FileInfo
 	Source   []byte
@@ -7,7 +11,9 @@ type FileReader struct {
 	Column   int
 	Index    int
 
-	start int
+	start       int
+	startLine   int
+	startColumn int
 }
 
 func NewFileReader(filename string) *FileReader {
@@ -78,6 +84,8 @@ func (r *FileReader) Shift(count int) {
 
 func (r *FileReader) StartToken() int {
 	r.start = r.Index
+	r.startLine = r.Line
+	r.startColumn = r.Column
 	return r.start
 }
 
@@ -85,8 +93,15 @@ func (r *FileReader) FinishToken(token Token) *TokenContext {
 	start := r.start
 	length := r.Index - r.start
 	content := string(r.Source[start:r.Index])
-	lineInfo := r.FileInfo.Lines[r.Line-1]
-	tokenInfo := lineInfo.NewToken(r.Column-length, length, content)
+
+	var tokenInfo *TokenInfo
+	if r.startLine == r.Line {
+		lineInfo := r.FileInfo.Lines[r.Line-1]
+		tokenInfo = lineInfo.NewToken(r.Column-length, length, content)
+
+	} else {
+		tokenInfo = r.newMultiLineToken(content)
+	}
 
 	context := &TokenContext{
 		Token:    token,
@@ -97,6 +112,29 @@ func (r *FileReader) FinishToken(token Token) *TokenContext {
 	return context
 }
 
+// newMultiLineToken splits a token across lines into pieces of each line. The
+// first piece starts where the token starts, and others start at column 1.
+func (r *FileReader) newMultiLineToken(content string) *TokenInfo {
+	var first, last *TokenInfo
+	line, column := r.startLine, r.startColumn
+
+	for _, piece := range strings.Split(content, "\n") {
+		lineInfo := r.FileInfo.Lines[line-1]
+		info := lineInfo.NewToken(column, len(piece), piece)
+		if first == nil {
+			first = info
+
+		} else {
+			last.Next = info
+		}
+
+		last = info
+		line, column = line+1, 1
+	}
+
+	return first
+}
+
 // Reject token starts from current position
 func (r *FileReader) RejectToken(length int) *TokenContext {
 	start := r.Index
@@ -28,6 +28,17 @@ const (
 	If
 	Else
 	Import
+	Throw
+	Try
+	Catch
+	Finally
+	Var
+	While
+	For
+	In
+	Break
+	Continue
+	Match
 	keywordEnd
 
 	operatorBegin
@@ -57,6 +68,8 @@ const (
 	Colon            // :
 	Semicolon        // ;
 	DualColon        // ::
+	Ellipsis         // ...
+	Arrow            // =>
 	bracketBegin     //
 	LParen           // (
 	RParen           // )
@@ -78,6 +91,17 @@ const (
 	SIf           = "if"
 	SElse         = "else"
 	SImport       = "import"
+	SThrow        = "throw"
+	STry          = "try"
+	SCatch        = "catch"
+	SFinally      = "finally"
+	SVar          = "var"
+	SWhile        = "while"
+	SFor          = "for"
+	SIn           = "in"
+	SBreak        = "break"
+	SContinue     = "continue"
+	SMatch        = "match"
 	SNull         = "null"
 	SFalse        = "false"
 	STrue         = "true"
@@ -105,6 +129,8 @@ const (
 	SColon        = ":"
 	SSemicolon    = ";"
 	SDualColon    = "::"
+	SEllipsis     = "..."
+	SArrow        = "=>"
 	SLParen       = "("
 	SRParen       = ")"
 	SLBrace       = "{"
@@ -124,6 +150,17 @@ var displayToken = [...]string{
 	If:        SIf,
 	Else:      SElse,
 	Import:    SImport,
+	Throw:     SThrow,
+	Try:       STry,
+	Catch:     SCatch,
+	Finally:   SFinally,
+	Var:       SVar,
+	While:     SWhile,
+	For:       SFor,
+	In:        SIn,
+	Break:     SBreak,
+	Continue:  SContinue,
+	Match:     SMatch,
 	Null:      SNull,
 	False:     SFalse,
 	True:      STrue,
@@ -151,6 +188,8 @@ var displayToken = [...]string{
 	Colon:     SColon,
 	Semicolon: SSemicolon,
 	DualColon: SDualColon,
+	Ellipsis:  SEllipsis,
+	Arrow:     SArrow,
 	LParen:    SLParen,
 	RParen:    SRParen,
 	LBrace:    SLBrace,
@@ -177,6 +216,17 @@ var displayName = [...]string{
 	If:           "IF",
 	Else:         "ELSE",
 	Import:       "IMPORT",
+	Throw:        "THROW",
+	Try:          "TRY",
+	Catch:        "CATCH",
+	Finally:      "FINALLY",
+	Var:          "VAR",
+	While:        "WHILE",
+	For:          "FOR",
+	In:           "IN",
+	Break:        "BREAK",
+	Continue:     "CONTINUE",
+	Match:        "MATCH",
 	Bang:         "BANG",
 	Plus:         "PLUS",
 	Minus:        "MINUS",
@@ -201,6 +251,8 @@ var displayName = [...]string{
 	Colon:        "COLON",
 	Semicolon:    "SEMICOLON",
 	DualColon:    "DUALCOLON",
+	Ellipsis:     "ELLIPSIS",
+	Arrow:        "ARROW",
 	LParen:       "LPAREN",
 	RParen:       "RPAREN",
 	LBrace:       "LBRACE",
@@ -255,15 +307,26 @@ func (t Token) IsOperator() bool {
 }
 
 var keywordMap = map[string]Token{
-	SLet:    Let,
-	SFn:     Fn,
-	SReturn: Return,
-	SIf:     If,
-	SElse:   Else,
-	SImport: Import,
-	SNull:   Null,
-	SFalse:  False,
-	STrue:   True,
+	SLet:      Let,
+	SFn:       Fn,
+	SReturn:   Return,
+	SIf:       If,
+	SElse:     Else,
+	SImport:   Import,
+	SThrow:    Throw,
+	STry:      Try,
+	SCatch:    Catch,
+	SFinally:  Finally,
+	SVar:      Var,
+	SWhile:    While,
+	SFor:      For,
+	SIn:       In,
+	SBreak:    Break,
+	SContinue: Continue,
+	SMatch:    Match,
+	SNull:     Null,
+	SFalse:    False,
+	STrue:     True,
 }
 
 // CheckKeywordToken returns keyword token when the given string is keyword,
@@ -301,6 +364,8 @@ var operatorMap = map[string]Token{
 	SColon:        Colon,
 	SSemicolon:    Semicolon,
 	SDualColon:    DualColon,
+	SEllipsis:     Ellipsis,
+	SArrow:        Arrow,
 	SLParen:       LParen,
 	SRParen:       RParen,
 	SLBrace:       LBrace,
@@ -109,6 +109,17 @@ func TestCheckKeywordToken(t *testing.T) {
 		{SIf, If},
 		{SElse, Else},
 		{SImport, Import},
+		{SThrow, Throw},
+		{STry, Try},
+		{SCatch, Catch},
+		{SFinally, Finally},
+		{SVar, Var},
+		{SWhile, While},
+		{SFor, For},
+		{SIn, In},
+		{SBreak, Break},
+		{SContinue, Continue},
+		{SMatch, Match},
 		{SNull, Null},
 		{SFalse, False},
 		{STrue, True},
@@ -143,6 +154,8 @@ func TestCheckOperatorToken(t *testing.T) {
 		{"||", OR},
 		{"=", Assign},
 		{".", Period},
+		{"...", Ellipsis},
+		{"=>", Arrow},
 		{",", Comma},
 		{":", Colon},
 		{";", Semicolon},
@@ -0,0 +1,266 @@
+package vm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+func funcInfo(args int, variadic bool, line int, column int) object.Object {
+	return object.NewHash([]object.HashPair{
+		{Key: object.NewString("arguments"), Value: object.NewInteger(int64(args))},
+		{Key: object.NewString("variadic"), Value: object.NewBoolean(variadic)},
+		{Key: object.NewString("file"), Value: object.NewString("testcase")},
+		{Key: object.NewString("line"), Value: object.NewInteger(int64(line))},
+		{Key: object.NewString("column"), Value: object.NewInteger(int64(column))},
+	})
+}
+
+func TestTypeOf(t *testing.T) {
+	tests := []vmTest{
+		{
+			`[typeof(1), typeof(1.5), typeof("s"), typeof(null), typeof(true)]`,
+			stack(object.NewArray([]object.Object{
+				object.NewString("INTEGER"),
+				object.NewString("FLOAT"),
+				object.NewString("STRING"),
+				object.NewString("NULL"),
+				object.NewString("BOOLEAN"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`[typeof([]), typeof({}), typeof(fn() {}), typeof(typeof(1))]`,
+			stack(object.NewArray([]object.Object{
+				object.NewString("ARRAY"),
+				object.NewString("HASH"),
+				object.NewString("FUNCTION"),
+				object.NewString("STRING"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Builtin functions are shadowed by variables.
+			`let typeof = fn(x) { "mine" }; typeof(1)`,
+			stack(object.NewString("mine")),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestTypePredicates(t *testing.T) {
+	tests := []vmTest{
+		{
+			`[is_int(1), is_int(1.0), is_float(1.0), is_string("1"), is_bool(false)]`,
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+				object.NewBoolean(true),
+				object.NewBoolean(true),
+				object.NewBoolean(true),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`[is_null(null), is_null(0), is_array([]), is_hash({}), is_hash([])]`,
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+				object.NewBoolean(true),
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Index and member arguments are single arguments.
+			text(
+				`let h = {"u": 1}; let a = [1.5];`,
+				`[100, typeof(h.u), is_int(a[0]), is_float(a[0])]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(100),
+				object.NewString("INTEGER"),
+				object.NewBoolean(false),
+				object.NewBoolean(true),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let apply = fn(f, x) {`,
+				`  if (!is_callable(f)) { throw "callback expected" }`,
+				`  f(x)`,
+				`};`,
+				`let ok, e = pcall(apply, 1, 2);`,
+				`[apply(fn(x) { x * 2 }, 2), ok, e]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(4),
+				object.NewBoolean(false),
+				object.NewString("callback expected"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestFuncInfo(t *testing.T) {
+	tests := []vmTest{
+		{
+			text(
+				`let f = fn(a, b) {`,
+				`  a + b`,
+				`};`,
+				`funcinfo(f)`,
+			),
+			stack(funcInfo(2, false, 1, 18)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`funcinfo(fn(a, ...rest) { rest })`,
+			stack(funcInfo(2, true, 1, 25)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Native functions are described like fn(...args).
+			`import "std/strings"; [is_callable(strings.upper), funcinfo(strings.upper)]`,
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewHash([]object.HashPair{
+					{Key: object.NewString("arguments"), Value: object.NewInteger(1)},
+					{Key: object.NewString("variadic"), Value: object.NewBoolean(true)},
+					{Key: object.NewString("file"), Value: object.NewNull()},
+					{Key: object.NewString("line"), Value: object.NewNull()},
+					{Key: object.NewString("column"), Value: object.NewNull()},
+				}),
+			})),
+			assertRegister(),
+		},
+		{
+			text(
+				`let check = fn(callback) {`,
+				`  is_callable(callback) && funcinfo(callback)["arguments"] == 1`,
+				`};`,
+				`[check(fn(x) { x }), check(fn(x, y) { x }), check(1)]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+				object.NewBoolean(false),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestFuncInfoOfMethod(t *testing.T) {
+	method, ok := object.NewInteger(1).OnMethod("abs")
+	if !ok {
+		t.Fatalf("method abs of INTEGER not found")
+	}
+
+	m := NewNaiveVM()
+	info, err := m.funcInfo(method)
+	if err != nil {
+		t.Fatalf("funcinfo of method error: %s", err)
+	}
+
+	expected := object.NewHash([]object.HashPair{
+		{Key: object.NewString("arguments"), Value: object.NewInteger(1)},
+		{Key: object.NewString("variadic"), Value: object.NewBoolean(true)},
+		{Key: object.NewString("file"), Value: object.NewNull()},
+		{Key: object.NewString("line"), Value: object.NewNull()},
+		{Key: object.NewString("column"), Value: object.NewNull()},
+	})
+	if !info.EqualTo(expected) {
+		t.Errorf("wrong funcinfo of method, expect %s, got %s", expected.Inspect(), info.Inspect())
+	}
+}
+
+// runIOTest runs code with input on both VMs, and returns their outputs.
+func runIOTest(t *testing.T, code string, input string) map[string]string {
+	t.Helper()
+
+	page := testCompileCode(t, code)
+	machines := map[string]VM{
+		"vme": NewNaiveVM(),
+		"vmi": NewNaiveVMInterpreter(),
+	}
+
+	outputs := make(map[string]string)
+	for name, m := range machines {
+		var output bytes.Buffer
+		m.SetOutput(&output)
+		m.SetInput(strings.NewReader(input))
+		m.LoadCodePage(page)
+		if _, err := m.Run(page.Main().Func(nil)); err != nil {
+			t.Fatalf("[%s] error: %s", name, err)
+		}
+
+		outputs[name] = output.String()
+	}
+
+	return outputs
+}
+
+func TestPrint(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`print("a", 1, 2.5)`, "a 1 2.5"},
+		{`print(); println(); println([1, "b"], {"c": null})`, "\n[1, b] {c: null}\n"},
+		{`let f = fn(x) { println("x =", x) }; f(1); f(true)`, "x = 1\nx = true\n"},
+		{`printf("%d-%s-%.2f-%t-%v", 42, "a", 1.5, false, [1])`, "42-a-1.50-false-[1]"},
+		{`printf("100%%\n")`, "100%\n"},
+		{`let r = print("a"); println(r == null)`, "atrue\n"},
+		{`let h = {"u": 1}; println(h.u)`, "1\n"},
+		{`let h = {"u": 1}; let a = [100, println(h.u)]; println(a)`, "1\n[100, null]\n"},
+	}
+
+	for _, c := range tests {
+		for name, output := range runIOTest(t, c.code, "") {
+			if output != c.expected {
+				t.Errorf("[%s] wrong output of %s, expect %q, got %q", name, c.code, c.expected, output)
+			}
+		}
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	code := text(
+		`var n = 0;`,
+		`var line = readline();`,
+		`while (line != null) {`,
+		`  println(n, line);`,
+		`  n = n + 1;`,
+		`  line = readline();`,
+		`}`,
+		`println("total", n)`,
+	)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "total 0\n"},
+		{"a\nb c\r\n\nd", "0 a\n1 b c\n2 \n3 d\ntotal 4\n"},
+	}
+
+	for _, c := range tests {
+		for name, output := range runIOTest(t, code, c.input) {
+			if output != c.expected {
+				t.Errorf("[%s] wrong output of input %q, expect %q, got %q", name, c.input, c.expected, output)
+			}
+		}
+	}
+}
@@ -2,10 +2,14 @@ package vm
 
 import (
 	"github.com/flily/macaque-lang/errors"
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/token"
 )
 
 type RuntimeError struct {
 	errors.BaseError
+	Value   object.Object  // thrown value, nil for errors raised by VM
+	Context *token.Context // source of instruction raising the error
 }
 
 func NewRuntimeError(format string, args ...interface{}) *RuntimeError {
@@ -16,3 +20,50 @@ func NewRuntimeError(format string, args ...interface{}) *RuntimeError {
 
 	return e
 }
+
+// NewThrowError creates error of value thrown by throw statement.
+func NewThrowError(value object.Object) *RuntimeError {
+	e := NewRuntimeError("uncaught exception: %s", value.Inspect())
+	e.Value = value
+	return e
+}
+
+func (e *RuntimeError) Error() string {
+	if e.Context == nil || len(e.Context.Tokens) == 0 {
+		return e.Message
+	}
+
+	return e.Context.Message("%s", e.Message)
+}
+
+// Object returns the value caught by catch block. It is the thrown value, or
+// a hash describing the error raised by VM.
+func (e *RuntimeError) Object() object.Object {
+	if e.Value != nil {
+		return e.Value
+	}
+
+	pairs := []object.HashPair{
+		{Key: object.NewString("message"), Value: object.NewString(e.Message)},
+	}
+
+	if e.Context != nil && len(e.Context.Tokens) > 0 {
+		first := e.Context.Tokens[0]
+		pairs = append(pairs,
+			object.HashPair{
+				Key:   object.NewString("file"),
+				Value: object.NewString(first.Filename()),
+			},
+			object.HashPair{
+				Key:   object.NewString("line"),
+				Value: object.NewInteger(int64(first.LineNo())),
+			},
+			object.HashPair{
+				Key:   object.NewString("column"),
+				Value: object.NewInteger(int64(first.ColumnStart())),
+			},
+		)
+	}
+
+	return object.NewHash(pairs)
+}
@@ -0,0 +1,453 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+func TestTryCatchStatement(t *testing.T) {
+	tests := []vmTest{
+		{
+			`try { throw "oops"; 1 } catch (e) { e }`,
+			stack(object.NewString("oops")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`try { 1 } catch (e) { 2 }`,
+			stack(object.NewInteger(1)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let a = 1;`,
+				`try {`,
+				`  let b = 2;`,
+				`  a + b + "3"`,
+				`} catch (e) {`,
+				`  [e["message"], e["line"], e["column"]]`,
+				`}`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewString("INTEGER PLUS(+) STRING is not accepted"),
+				object.NewInteger(4),
+				object.NewInteger(3),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Runtime errors instead of panic in Go.
+			text(
+				`let div = fn(a, b) { try { a / b } catch (e) { e["message"] } };`,
+				`let mod = fn(a, b) { try { a % b } catch (e) { e["message"] } };`,
+				`[div(1, 0), mod(1, 0), div(1.0, 0) > 0]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewString("integer divided by zero"),
+				object.NewString("integer divided by zero"),
+				object.NewBoolean(true),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`try {`,
+				`  try { throw 1 } catch (e) { throw e + 1 }`,
+				`} catch (e) {`,
+				`  e * 10`,
+				`}`,
+			),
+			stack(object.NewInteger(20)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(n) {`,
+				`  if (n == 0) { throw "bottom" }`,
+				`  fn(n - 1) + 1`,
+				`};`,
+				`let g = fn(n) {`,
+				`  try { f(n) } catch (e) { return e }`,
+				`};`,
+				`g(10), g(0), 42`,
+			),
+			stack(
+				object.NewInteger(42),
+				object.NewString("bottom"),
+				object.NewString("bottom"),
+			),
+			assertRegister(sp(3), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestFinallyStatement(t *testing.T) {
+	tests := []vmTest{
+		{
+			`try { 1 } finally { 2 }`,
+			stack(object.NewInteger(1)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Finally block runs when try block finishes.
+			text(
+				`try {`,
+				`  try { 1 } finally { throw "finally" }`,
+				`} catch (e) { e }`,
+			),
+			stack(object.NewString("finally")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Finally block runs when exception is thrown.
+			text(
+				`try {`,
+				`  try { throw 1 } finally { throw 2 }`,
+				`} catch (e) { e }`,
+			),
+			stack(object.NewInteger(2)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Finally block runs when exception is thrown in catch block.
+			text(
+				`try {`,
+				`  try { throw 1 } catch (e) { throw e + 1 } finally { 3 }`,
+				`} catch (e) { e }`,
+			),
+			stack(object.NewInteger(2)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Finally block runs when function returns.
+			text(
+				`let f = fn() {`,
+				`  try { return 1 } finally { throw 3 }`,
+				`};`,
+				`try { f() } catch (e) { e }`,
+			),
+			stack(object.NewInteger(3)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(x) {`,
+				`  try {`,
+				`    try { return x } finally { 1 }`,
+				`  } catch (e) {`,
+				`    return 0`,
+				`  }`,
+				`};`,
+				`f(5) + f(6)`,
+			),
+			stack(object.NewInteger(11)),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestUncaughtException(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{
+			`throw 42`,
+			"uncaught exception: 42",
+		},
+		{
+			`let f = fn() { throw "oops" }; try { f() } finally { 1 }`,
+			"uncaught exception: oops",
+		},
+		{
+			`try { throw 1 } catch (e) { e + "a" }`,
+			"INTEGER PLUS(+) STRING is not accepted",
+		},
+		{
+			`let f = fn(...xs) { xs }; f(...1)`,
+			"INTEGER can not be spread",
+		},
+		{
+			`let a = [1]; a[1] = 2`,
+			"ARRAY[INTEGER] can not be assigned",
+		},
+		{
+			`for (x in 1.5) { x }`,
+			"FLOAT is not iterable",
+		},
+		{
+			`let h = {}; h[1:]`,
+			"HASH[INTEGER:NULL] is not accepted",
+		},
+		{
+			`printf(1)`,
+			"printf requires a STRING format, but got INTEGER",
+		},
+		{
+			`funcinfo(1)`,
+			"INTEGER is not a function",
+		},
+		{
+			`1 / 0`,
+			"integer divided by zero",
+		},
+		{
+			`let f = fn(n) { fn(n + 1) + 1 }; f(0)`,
+			"stack overflow",
+		},
+	}
+
+	for _, c := range tests {
+		page := testCompileCode(t, c.code)
+		machines := map[string]VM{
+			"vme": NewNaiveVM(),
+			"vmi": NewNaiveVMInterpreter(),
+		}
+
+		for name, m := range machines {
+			m.LoadCodePage(page)
+			_, err := m.Run(page.Main().Func(nil))
+			if err == nil {
+				t.Fatalf("[%s] expect error in code: %s", name, c.code)
+			}
+
+			if !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("[%s] wrong error, expect %q, got:\n%s", name, c.expected, err)
+			}
+		}
+	}
+}
+
+func TestProtectedCall(t *testing.T) {
+	tests := []vmTest{
+		{
+			text(
+				`let ok, v = pcall(fn(x) { x + 1 }, 41);`,
+				`[ok, v]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewInteger(42),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(a, b) { return b, a };`,
+				`let ok, x, y = pcall(f, 1, 2);`,
+				`[ok, x, y]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewInteger(2),
+				object.NewInteger(1),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(n) {`,
+				`  if (n == 0) { throw "bottom" }`,
+				`  fn(n - 1) + 1`,
+				`};`,
+				`let ok, err = pcall(f, 10);`,
+				`[ok, err]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewString("bottom"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let ok, err = pcall(fn() { 1 + "a" });`,
+				`[ok, err["message"], err["line"]]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewString("INTEGER PLUS(+) STRING is not accepted"),
+				object.NewInteger(1),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let ok, err = pcall(5);`,
+				`[ok, err["message"]]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewString("INTEGER is not callable"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Member of module or hash is called as a single callable.
+			text(
+				`import "std/strings";`,
+				`let h = {"f": fn(a, b) { a - b }};`,
+				`let ok1, v1 = pcall(strings.upper, "x");`,
+				`let ok2, v2 = pcall(h.f, 5, 2);`,
+				`[ok1, v1, ok2, v2]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewString("X"),
+				object.NewBoolean(true),
+				object.NewInteger(3),
+			})),
+			assertRegister(),
+		},
+		{
+			text(
+				`let f = fn(n) { fn(n + 1) + 1 };`,
+				`let ok1, e1 = pcall(f, 0);`,
+				`let ok2, e2 = pcall(fn() { 1 % 0 });`,
+				`let g = fn() { try { f(0) } catch (e) { e["message"] } };`,
+				`[ok1, e1["message"], ok2, e2["message"], g()]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewString("stack overflow"),
+				object.NewBoolean(false),
+				object.NewString("integer divided by zero"),
+				object.NewString("stack overflow"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Exceptions caught inside are not seen by pcall.
+			text(
+				`let f = fn() { try { throw 1 } catch (e) { return e + 1 } };`,
+				`let g = fn() { let ok, v = pcall(f); if (ok) { v } else { 0 } };`,
+				`try { g() + 1 } catch (e) { -1 }`,
+			),
+			stack(object.NewInteger(3)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Exception in protected call is not caught by outer try.
+			text(
+				`try {`,
+				`  let ok, e = pcall(fn() { throw "inner" });`,
+				`  e`,
+				`} catch (e) { "outer" }`,
+			),
+			stack(object.NewString("inner")),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestProtectedCallWithMessageHandler(t *testing.T) {
+	tests := []vmTest{
+		{
+			text(
+				`let ok, v = xpcall(fn(x) { x * 2 }, fn(e) { "handled" }, 21);`,
+				`[ok, v]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewInteger(42),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn() { throw 5 };`,
+				`let ok, v = xpcall(f, fn(e) { e * 10 });`,
+				`[ok, v]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewInteger(50),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Exception thrown in message handler.
+			text(
+				`let f = fn() { throw 5 };`,
+				`let ok, v = xpcall(f, fn(e) { throw e + 1 });`,
+				`[ok, v]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewInteger(6),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let ok, v = xpcall(null, fn(e) { e["message"] });`,
+				`[ok, v]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewString("NULL is not callable"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`import "std/arrays";`,
+				`let h = fn(e) { e["message"] };`,
+				`let ok1, v1 = xpcall(arrays.first, h, [7]);`,
+				`let ok2, v2 = xpcall(arrays.first, h, 7);`,
+				`[ok1, v1, ok2, v2]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewInteger(7),
+				object.NewBoolean(false),
+				object.NewString("first requires an ARRAY as argument 1, but got INTEGER"),
+			})),
+			assertRegister(),
+		},
+		{
+			// Message handler runs on top of the overflowed stack.
+			text(
+				`let f = fn(n) { fn(n + 1) + 1 };`,
+				`let ok, v = xpcall(f, fn(e) { [e["message"], is_int(1)] }, 0);`,
+				`[ok, v]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewArray([]object.Object{
+					object.NewString("stack overflow"),
+					object.NewBoolean(true),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Message handler runs before the stack is unwound.
+			text(
+				`let f = fn(n) {`,
+				`  if (n == 0) { throw "bottom" }`,
+				`  fn(n - 1) + 1`,
+				`};`,
+				`let ok, v = xpcall(f, fn(e) { traceback() }, 2);`,
+				`v`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewString("testcase:5:31"),
+				object.NewString("testcase:2:17"),
+				object.NewString("testcase:3:3"),
+				object.NewString("testcase:3:3"),
+				object.NewString("testcase:5:13"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
@@ -61,3 +61,525 @@ func TestIfExpression(t *testing.T) {
 
 	runVMTest(t, tests)
 }
+
+func TestFloatExpressions(t *testing.T) {
+	tests := []vmTest{
+		{
+			`1.5 + 2.25`,
+			stack(object.NewFloat(3.75)),
+			assertRegister(sp(1)),
+		},
+		{
+			`-0.5 * 4`,
+			stack(object.NewFloat(-2)),
+			assertRegister(sp(1)),
+		},
+		{
+			`7 / 2.0, 7 / 2`,
+			stack(object.NewInteger(3), object.NewFloat(3.5)),
+			assertRegister(sp(2)),
+		},
+		{
+			`1 == 1.0, 2 < 2.5`,
+			stack(object.NewBoolean(true), object.NewBoolean(true)),
+			assertRegister(sp(2)),
+		},
+		{
+			text(
+				`let half = fn(x) { x / 2.0 };`,
+				`half(5)`,
+			),
+			stack(object.NewFloat(2.5)),
+			assertRegister(sp(1)),
+		},
+		{
+			`{1: "one"}[1.0]`,
+			stack(object.NewString("one")),
+			assertRegister(sp(1)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestShortCircuitExpression(t *testing.T) {
+	tests := []vmTest{
+		{
+			`true && 42`,
+			stack(object.NewInteger(42)),
+			assertRegister(sp(1)),
+		},
+		{
+			`false && 42`,
+			stack(object.NewBoolean(false)),
+			assertRegister(sp(1)),
+		},
+		{
+			`null || "default"`,
+			stack(object.NewString("default")),
+			assertRegister(sp(1)),
+		},
+		{
+			`"value" || "default"`,
+			stack(object.NewString("value")),
+			assertRegister(sp(1)),
+		},
+		{
+			`1 > 2 || 2 > 1 && 3`,
+			stack(object.NewInteger(3)),
+			assertRegister(sp(1)),
+		},
+		{
+			// right operand calls null, which fails if it is evaluated.
+			text(
+				`let x = null;`,
+				`x != null && x(1)`,
+			),
+			stack(object.NewBoolean(false)),
+			assertRegister(sp(1)),
+		},
+		{
+			text(
+				`let x = [1, 2];`,
+				`let f = null;`,
+				`x != null || f(), x[1]`,
+			),
+			stack(object.NewInteger(2), object.NewBoolean(true)),
+			assertRegister(sp(2)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestVariadicFunction(t *testing.T) {
+	tests := []vmTest{
+		{
+			text(
+				`let f = fn(a, ...rest) { [a, rest] };`,
+				`f(1, 2, 3)`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewArray([]object.Object{
+					object.NewInteger(2),
+					object.NewInteger(3),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(a, b, ...rest) { [a, b, rest] };`,
+				`f(1)`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewNull(),
+				object.NewArray([]object.Object{}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Missing arguments are null, and extra arguments are dropped.
+			text(
+				`let f = fn(a, b) { [a, b] };`,
+				`f(1), f(1, 2, 3)`,
+			),
+			stack(
+				object.NewArray([]object.Object{
+					object.NewInteger(1),
+					object.NewInteger(2),
+				}),
+				object.NewArray([]object.Object{
+					object.NewInteger(1),
+					object.NewNull(),
+				}),
+			),
+			assertRegister(sp(2), bp(0)),
+		},
+		{
+			text(
+				`let sum = fn(...xs) {`,
+				`  if (xs == []) { return 0 }`,
+				`  xs[0]`,
+				`};`,
+				`sum(), sum(5, 6)`,
+			),
+			stack(object.NewInteger(5), object.NewInteger(0)),
+			assertRegister(sp(2), bp(0)),
+		},
+		{
+			// Index and member arguments are single values.
+			text(
+				`let f = fn(x, y) { [x, y] };`,
+				`let h = {"u": 1}; let a = [7];`,
+				`[100, f(5, h.u), f(a[0], h["u"])]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(100),
+				object.NewArray([]object.Object{
+					object.NewInteger(5),
+					object.NewInteger(1),
+				}),
+				object.NewArray([]object.Object{
+					object.NewInteger(7),
+					object.NewInteger(1),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(x, ...rest) { [x, rest] };`,
+				`let h = {"u": 1}; let a = [7];`,
+				`[100, f(5, h.u, a[0])]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(100),
+				object.NewArray([]object.Object{
+					object.NewInteger(5),
+					object.NewArray([]object.Object{
+						object.NewInteger(1),
+						object.NewInteger(7),
+					}),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let ok, v = pcall(fn(...xs) { xs }, 1, 2);`,
+				`[ok, v]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewArray([]object.Object{
+					object.NewInteger(1),
+					object.NewInteger(2),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestSpreadArguments(t *testing.T) {
+	tests := []vmTest{
+		{
+			text(
+				`let f = fn(a, b, c) { [a, b, c] };`,
+				`let xs = [2, 3];`,
+				`f(1, ...xs)`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(2),
+				object.NewInteger(3),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(a, ...rest) { [a, rest] };`,
+				`f(...[1, 2], 3, ...[])`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewArray([]object.Object{
+					object.NewInteger(2),
+					object.NewInteger(3),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(n, ...rest) {`,
+				`  if (n == 0) { return rest }`,
+				`  fn(n - 1, ...rest, n)`,
+				`};`,
+				`f(3)`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(3),
+				object.NewInteger(2),
+				object.NewInteger(1),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestMethodCall(t *testing.T) {
+	tests := []vmTest{
+		{
+			// Member of hash is called with the hash as the first argument.
+			text(
+				`let counter = {"n": 1, "get": fn(self, d) { self.n + d }};`,
+				`counter::get(10)`,
+			),
+			stack(object.NewInteger(11)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`"abc"::upper() + "xyz"::length()::to_string()`,
+			stack(object.NewString("ABC3")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`var sum = 0;`,
+				`5::times(fn(i) { sum = sum + i });`,
+				`sum`,
+			),
+			stack(object.NewInteger(10)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`[1, 2, 3]::map(fn(x) { x * x })::filter(fn(x) { x > 1 })`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(4),
+				object.NewInteger(9),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`[1, 2, 3, 4]::reduce(fn(acc, x) { acc + x }, 0)`,
+			stack(object.NewInteger(10)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`let h = {"a": 1, "b": 2}; [h::keys(), h::values(), h::has("a"), h::length()]`,
+			stack(object.NewArray([]object.Object{
+				object.NewArray([]object.Object{
+					object.NewString("a"),
+					object.NewString("b"),
+				}),
+				object.NewArray([]object.Object{
+					object.NewInteger(1),
+					object.NewInteger(2),
+				}),
+				object.NewBoolean(true),
+				object.NewInteger(2),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Member of hash shadows the native method.
+			`let h = {"keys": fn(self) { "mine" }}; h::keys()`,
+			stack(object.NewString("mine")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Method call in tail position, and callbacks calling methods.
+			text(
+				`let shout = fn(words) { words::map(fn(w) { w::upper() })::join(" ") };`,
+				`shout(["hello", "world"])`,
+			),
+			stack(object.NewString("HELLO WORLD")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Exceptions raised in callbacks are caught outside.
+			text(
+				`let f = fn() {`,
+				`  try {`,
+				`    [1, 2]::map(fn(x) { if (x > 1) { throw "too big" }; x })`,
+				`  } catch (e) {`,
+				`    e`,
+				`  }`,
+				`};`,
+				`[f(), [1]::map(fn(x) { try { throw x } catch (e) { e + 1 } })]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewString("too big"),
+				object.NewArray([]object.Object{
+					object.NewInteger(2),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let ok, e = pcall(fn() { 1::missing() });`,
+				`[ok, e.message]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewString("INTEGER has no method missing"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestSliceExpression(t *testing.T) {
+	tests := []vmTest{
+		{
+			`let a = [1, 2, 3, 4]; [a[1:3], a[:-2], a[-1:], a[:]]`,
+			stack(object.NewArray([]object.Object{
+				object.NewArray([]object.Object{
+					object.NewInteger(2),
+					object.NewInteger(3),
+				}),
+				object.NewArray([]object.Object{
+					object.NewInteger(1),
+					object.NewInteger(2),
+				}),
+				object.NewArray([]object.Object{
+					object.NewInteger(4),
+				}),
+				object.NewArray([]object.Object{
+					object.NewInteger(1),
+					object.NewInteger(2),
+					object.NewInteger(3),
+					object.NewInteger(4),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`let s = "hello"; [s[1:3], s[:-1], s[3:], s[4:2]]`,
+			stack(object.NewArray([]object.Object{
+				object.NewString("el"),
+				object.NewString("hell"),
+				object.NewString("lo"),
+				object.NewString(""),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Slices share elements with the array.
+			`let a = [1, 2, 3]; let b = a[1:]; b[0] = 20; a`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(20),
+				object.NewInteger(3),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let sum = fn(a, acc) {`,
+				`  if (a[0] == null) { return acc }`,
+				`  fn(a[1:], acc + a[0])`,
+				`};`,
+				`sum([1, 2, 3, 4, 5], 0)`,
+			),
+			stack(object.NewInteger(15)),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestInterpolatedString(t *testing.T) {
+	tests := []vmTest{
+		{
+			`let name = "bob"; let age = 41; "hello ${name}, you are ${age + 1}"`,
+			stack(object.NewString("hello bob, you are 42")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`let price = 5; "\${price} is \$${price}"`,
+			stack(object.NewString("${price} is $5")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`let a = [1, 2]; "${a}${null}${1.5}${{"k": "${a[1]}"}.k}"`,
+			stack(object.NewString("[1, 2]null1.52")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`let f = fn() { 1, 2 }; ["${f()}", "${fn(x) { x }(3)}"]`,
+			stack(object.NewArray([]object.Object{
+				object.NewString("2"),
+				object.NewString("3"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Raw strings are not interpolated.
+			"let b = 1; /* comment */ `${b} \\n\n` + \"${b}\"",
+			stack(object.NewString("${b} \\n\n1")),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []vmTest{
+		{
+			text(
+				`let f = fn(v) {`,
+				`    match (v) {`,
+				`        0 => "zero",`,
+				`        -1 => "minus",`,
+				`        [] => "empty",`,
+				`        [x] => x,`,
+				`        [1, _, ...rest] => rest,`,
+				`        {kind: "circle", r} => r * r,`,
+				`        {"w": w, h} if w > 0 => w * h,`,
+				`        _ => "other",`,
+				`    }`,
+				`};`,
+				`[f(0), f(-1), f([]), f([7]), f([1, 2, 3]), f({"kind": "circle", "r": 3}),`,
+				` f({"w": 2, "h": 5}), f({"w": 0, "h": 5}), f("0"), f([2, 3])]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewString("zero"),
+				object.NewString("minus"),
+				object.NewString("empty"),
+				object.NewInteger(7),
+				object.NewArray([]object.Object{
+					object.NewInteger(3),
+				}),
+				object.NewInteger(9),
+				object.NewInteger(10),
+				object.NewString("other"),
+				object.NewString("other"),
+				object.NewString("other"),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let sum = fn(a) { match (a) { [] => 0, [x, ...r] => x + fn(r) } };`,
+				`sum([1, 2, 3, 4])`,
+			),
+			stack(object.NewInteger(10)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Value is null if no arm matches.
+			`let x = 3; match (x) { 1 => 2, [y] => y }`,
+			stack(object.NewNull()),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// The first value of subject is matched, as destructuring let.
+			text(
+				`let f = fn() { [2, {"k": 3}], 1 };`,
+				`let [b, h] = f();`,
+				`[match (f()) { [a, {k}] => a + k, n => n }, b + h["k"]]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(5),
+				object.NewInteger(5),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
@@ -0,0 +1,247 @@
+package vm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flily/macaque-lang/compiler"
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/opcode"
+)
+
+type moduleFile struct {
+	name    string
+	content string
+}
+
+func module(name string, lines ...string) moduleFile {
+	return moduleFile{name, text(lines...)}
+}
+
+func testCompileCodeWithModules(t *testing.T, code string, modules []moduleFile) *opcode.CodePage {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, m := range modules {
+		filename := filepath.Join(dir, filepath.FromSlash(m.name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("create directory failed: %s", err)
+		}
+
+		if err := os.WriteFile(filename, []byte(m.content), 0644); err != nil {
+			t.Fatalf("write module %s failed: %s", m.name, err)
+		}
+	}
+
+	c := compiler.NewCompiler()
+	block, err := c.CompileCode(filepath.Join(dir, "main.mq"), []byte(code))
+	if err != nil {
+		t.Fatalf("compiler error:\n%s", err)
+	}
+
+	return c.Link(block)
+}
+
+type vmImportTest struct {
+	modules []moduleFile
+	code    string
+	stack   []object.Object
+}
+
+func runVMImportTest(t *testing.T, cases []vmImportTest) {
+	t.Helper()
+
+	for _, c := range cases {
+		for name, m := range map[string]VM{"vme": NewNaiveVM(), "vmi": NewNaiveVMInterpreter()} {
+			page := testCompileCodeWithModules(t, c.code, c.modules)
+			m.LoadCodePage(page)
+			_, err := m.Run(page.Main().Func(nil))
+			if err != nil {
+				t.Fatalf("%s error: %s", name, err)
+			}
+
+			checkVMStackTop(t, name, m, c.stack)
+		}
+	}
+}
+
+func TestImportStatement(t *testing.T) {
+	tests := []vmImportTest{
+		{
+			modules: []moduleFile{
+				module("math.mq",
+					`let add = fn(a, b) { a + b };`,
+					`{"add": add, "pi": 3}`,
+				),
+			},
+			code: text(
+				`import "math";`,
+				`math["add"](1, math["pi"])`,
+			),
+			stack: stack(object.NewInteger(4)),
+		},
+		{
+			modules: []moduleFile{
+				module("lib/greeting.mq",
+					`let prefix = "hello, ";`,
+					`fn(name) { prefix + name }`,
+				),
+			},
+			code: text(
+				`import hello "lib/greeting";`,
+				`let answer = 42;`,
+				`hello("world")`,
+			),
+			stack: stack(object.NewString("hello, world")),
+		},
+		{
+			modules: []moduleFile{
+				module("counter.mq",
+					`let x = 1;`,
+					`let y = 2;`,
+					`[x, y]`,
+				),
+			},
+			code: text(
+				`import a "counter";`,
+				`import b "counter.mq";`,
+				`a == b`,
+			),
+			stack: stack(object.NewBoolean(true)),
+		},
+		{
+			modules: []moduleFile{
+				module("empty.mq",
+					`let x = 1;`,
+				),
+			},
+			code: text(
+				`import "empty";`,
+				`empty`,
+			),
+			stack: stack(object.NewNull()),
+		},
+		{
+			modules: []moduleFile{
+				module("a.mq",
+					`import "b";`,
+					`b + 1`,
+				),
+				module("b.mq",
+					`40 + 1`,
+				),
+			},
+			code: text(
+				`import "a";`,
+				`import "b";`,
+				`a + b`,
+			),
+			stack: stack(object.NewInteger(83)),
+		},
+		{
+			// Functions called by top level of module return all values.
+			modules: []moduleFile{
+				module("pair.mq",
+					`let two = fn() { return 1, 2; };`,
+					`let a, b = two();`,
+					`return [a, b];`,
+				),
+			},
+			code: text(
+				`import p "pair";`,
+				`import q "pair";`,
+				`[p, p == q]`,
+			),
+			stack: stack(object.NewArray([]object.Object{
+				object.NewArray([]object.Object{
+					object.NewInteger(1),
+					object.NewInteger(2),
+				}),
+				object.NewBoolean(true),
+			})),
+		},
+		{
+			// Module is exported as ONE value, even returned by a call.
+			modules: []moduleFile{
+				module("last.mq",
+					`let two = fn() { return 3, 4; };`,
+					`return two();`,
+				),
+			},
+			code: text(
+				`import "last";`,
+				`import again "last";`,
+				`[last, again]`,
+			),
+			stack: stack(object.NewArray([]object.Object{
+				object.NewInteger(3),
+				object.NewInteger(3),
+			})),
+		},
+	}
+
+	runVMImportTest(t, tests)
+}
+
+func TestImportModuleVariables(t *testing.T) {
+	tests := []vmImportTest{
+		{
+			modules: []moduleFile{
+				module("counter.mq",
+					`var @count = 0;`,
+					`fn() { @count = @count + 1 }`,
+				),
+			},
+			code: text(
+				`var @count = 10;`,
+				`import incr "counter";`,
+				`incr(); incr();`,
+				`[@count, incr()]`,
+			),
+			stack: stack(object.NewArray([]object.Object{
+				object.NewInteger(10),
+				object.NewInteger(3),
+			})),
+		},
+		{
+			modules: []moduleFile{
+				module("config.mq",
+					`var $level = 1;`,
+					`fn(n) { $level = n }`,
+				),
+			},
+			code: text(
+				`import setLevel "config";`,
+				`let before = $level;`,
+				`setLevel(5);`,
+				`[before, $level]`,
+			),
+			stack: stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(5),
+			})),
+		},
+		{
+			modules: []moduleFile{
+				module("a.mq",
+					`let @name = "a";`,
+					`fn() { @name }`,
+				),
+				module("b.mq",
+					`let @name = "b";`,
+					`fn() { @name }`,
+				),
+			},
+			code: text(
+				`import a "a";`,
+				`import b "b";`,
+				`let @name = "main";`,
+				`a() + b() + @name`,
+			),
+			stack: stack(object.NewString("abmain")),
+		},
+	}
+
+	runVMImportTest(t, tests)
+}
@@ -0,0 +1,105 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+// SetOutput sets the writer which print, println and printf write to.
+func (m *NaiveVMBase) SetOutput(w io.Writer) {
+	m.output = w
+}
+
+// SetInput sets the reader which readline reads from.
+func (m *NaiveVMBase) SetInput(r io.Reader) {
+	m.input = bufio.NewReader(r)
+}
+
+// popArguments pops n arguments of a call, the first argument is on the top of
+// the stack.
+func (m *NaiveVMBase) popArguments(n int) []object.Object {
+	values := m.stackPopNWithValue(n)
+	args := make([]object.Object, n)
+	for i := 0; i < n; i++ {
+		args[i] = values[n-1-i]
+	}
+
+	return args
+}
+
+// print writes args separated by spaces to output, and a newline if newline is
+// set.
+func (m *NaiveVMBase) print(args []object.Object, newline bool) error {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg.Inspect()
+	}
+
+	s := strings.Join(parts, " ")
+	if newline {
+		s += "\n"
+	}
+
+	return m.write(s)
+}
+
+// printf writes args in format of args[0] to output. Format is the same as
+// package fmt of Go, integers, floats, strings and booleans are formatted as
+// values of Go, while other values are formatted in their inspect forms.
+func (m *NaiveVMBase) printf(args []object.Object) error {
+	format, ok := args[0].(*object.StringObject)
+	if !ok {
+		return NewRuntimeError("printf requires a STRING format, but got %s", args[0].Type())
+	}
+
+	values := make([]interface{}, len(args)-1)
+	for i, arg := range args[1:] {
+		switch v := arg.(type) {
+		case *object.IntegerObject:
+			values[i] = v.Value
+
+		case *object.FloatObject:
+			values[i] = v.Value
+
+		case *object.StringObject:
+			values[i] = v.Value
+
+		case *object.BooleanObject:
+			values[i] = v.Value
+
+		default:
+			values[i] = v.Inspect()
+		}
+	}
+
+	return m.write(fmt.Sprintf(format.Value, values...))
+}
+
+func (m *NaiveVMBase) write(s string) error {
+	if _, err := io.WriteString(m.output, s); err != nil {
+		return NewRuntimeError("write output error: %s", err)
+	}
+
+	return nil
+}
+
+// readLine reads a line from input, without the line ending. It returns null
+// at the end of input.
+func (m *NaiveVMBase) readLine() (object.Object, error) {
+	line, err := m.input.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, NewRuntimeError("read input error: %s", err)
+	}
+
+	if err == io.EOF && len(line) == 0 {
+		return null, nil
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return object.NewString(line), nil
+}
@@ -0,0 +1,136 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/flily/macaque-lang/object"
+	"github.com/flily/macaque-lang/std"
+	"github.com/flily/macaque-lang/token"
+)
+
+// NativeFunction is a function provided by host code. It is called with the VM
+// running it, and may return multiple values.
+type NativeFunction func(vm VM, args []object.Object) ([]object.Object, error)
+
+// NativeFunctionObject is a native function as a value in scripts.
+type NativeFunctionObject struct {
+	Name     string
+	Function NativeFunction
+}
+
+func NewNativeFunction(name string, f NativeFunction) *NativeFunctionObject {
+	o := &NativeFunctionObject{
+		Name:     name,
+		Function: f,
+	}
+
+	return o
+}
+
+func (f *NativeFunctionObject) Type() object.ObjectType {
+	return object.ObjectTypeFunction
+}
+
+func (f *NativeFunctionObject) Inspect() string {
+	return fmt.Sprintf("native[%s]", f.Name)
+}
+
+func (f *NativeFunctionObject) Hashable() bool {
+	return false
+}
+
+func (f *NativeFunctionObject) HashKey() interface{} {
+	return nil
+}
+
+func (f *NativeFunctionObject) EqualTo(o object.Object) bool {
+	switch v := o.(type) {
+	case *NativeFunctionObject:
+		return f == v
+	}
+
+	return false
+}
+
+func (f *NativeFunctionObject) OnPrefix(t token.Token) (object.Object, bool) {
+	if t == token.Bang {
+		return object.NewBoolean(false), true
+	}
+
+	return nil, false
+}
+
+func (f *NativeFunctionObject) OnInfix(t token.Token, o object.Object) (object.Object, bool) {
+	switch t {
+	case token.EQ:
+		return object.NewBoolean(f.EqualTo(o)), true
+
+	case token.NE:
+		return object.NewBoolean(!f.EqualTo(o)), true
+	}
+
+	return nil, false
+}
+
+func (f *NativeFunctionObject) OnIndex(o object.Object) (object.Object, bool) {
+	return nil, false
+}
+
+func (f *NativeFunctionObject) OnAssignIndex(index object.Object, value object.Object) bool {
+	return false
+}
+
+func (f *NativeFunctionObject) OnSlice(low object.Object, high object.Object) (object.Object, bool) {
+	return nil, false
+}
+
+func (f *NativeFunctionObject) OnMethod(name string) (object.Object, bool) {
+	m, ok := object.LookupMethod(f.Type(), name)
+	if !ok {
+		return nil, false
+	}
+
+	return m, true
+}
+
+// RegisterNative registers native function f with name, which is bound by the
+// compiler before compiling. An existing function with the same name is
+// replaced.
+func (m *NaiveVMBase) RegisterNative(name string, f NativeFunction) {
+	if m.natives == nil {
+		m.natives = make(map[string]*NativeFunctionObject)
+	}
+
+	m.natives[name] = NewNativeFunction(name, f)
+}
+
+// registerStandardLibrary registers functions of all system modules, by the
+// native names which the compiler binds when the modules are imported.
+func (m *NaiveVMBase) registerStandardLibrary() {
+	for _, module := range std.Modules() {
+		for name, f := range module.Functions {
+			m.RegisterNative(module.NativeName(name), nativeOfStd(f))
+		}
+	}
+}
+
+func nativeOfStd(f std.Function) NativeFunction {
+	return func(vm VM, args []object.Object) ([]object.Object, error) {
+		return f(vm, args)
+	}
+}
+
+// loadNative finds native function i of the code page by its name.
+func (m *NaiveVMBase) loadNative(i int) (object.Object, error) {
+	if i < 0 || i >= len(m.NativeNames) {
+		return nil, NewRuntimeError("native function %d not found", i)
+	}
+
+	name := m.NativeNames[i]
+	f, ok := m.natives[name]
+	if !ok {
+		return nil, NewRuntimeError("native function %s is not registered", name)
+	}
+
+	return f, nil
+}
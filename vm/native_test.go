@@ -0,0 +1,344 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/flily/macaque-lang/compiler"
+	"github.com/flily/macaque-lang/object"
+)
+
+var testNatives = map[string]NativeFunction{
+	"divmod": func(vm VM, args []object.Object) ([]object.Object, error) {
+		a := args[0].(*object.IntegerObject).Value
+		b := args[1].(*object.IntegerObject).Value
+		return []object.Object{object.NewInteger(a / b), object.NewInteger(a % b)}, nil
+	},
+	"fail": func(vm VM, args []object.Object) ([]object.Object, error) {
+		return nil, fmt.Errorf("fail with %d arguments", len(args))
+	},
+	"apply": func(vm VM, args []object.Object) ([]object.Object, error) {
+		return vm.CallFunction(args[0], args[1:]...)
+	},
+	"sort": func(vm VM, args []object.Object) ([]object.Object, error) {
+		elements := append([]object.Object{}, args[0].(*object.ArrayObject).Elements...)
+		less := args[1].(*object.FunctionObject)
+		for i := 1; i < len(elements); i++ {
+			for j := i; j > 0; j-- {
+				r, err := vm.Call(less, elements[j], elements[j-1])
+				if err != nil {
+					return nil, err
+				}
+
+				if !object.IsTrue(r[0]) {
+					break
+				}
+
+				elements[j], elements[j-1] = elements[j-1], elements[j]
+			}
+		}
+
+		return []object.Object{object.NewArray(elements)}, nil
+	},
+	"nothing": func(vm VM, args []object.Object) ([]object.Object, error) {
+		return nil, nil
+	},
+	"counter": func(vm VM, args []object.Object) ([]object.Object, error) {
+		n := 0
+		return []object.Object{object.NewUserValue("counter", &n, testCounter{})}, nil
+	},
+}
+
+// testCounter is behavior of user value made by native function counter.
+type testCounter struct {
+	object.BaseUserBehavior
+}
+
+func (c testCounter) OnIndex(self *object.UserValue, index object.Object) (object.Object, bool) {
+	return object.NewInteger(int64(*self.Value.(*int))), true
+}
+
+func (c testCounter) OnMethod(self *object.UserValue, name string) (object.Object, bool) {
+	if name != "incr" {
+		return nil, false
+	}
+
+	m := &object.MethodObject{
+		Name: "incr",
+		Method: func(c object.Caller, self object.Object, args []object.Object) ([]object.Object, error) {
+			*self.(*object.UserValue).Value.(*int)++
+			return []object.Object{self}, nil
+		},
+	}
+
+	return m, true
+}
+
+func runNativeTest(t *testing.T, cases []vmTest) {
+	t.Helper()
+
+	for _, c := range cases {
+		machines := map[string]VM{
+			"vme": NewNaiveVM(),
+			"vmi": NewNaiveVMInterpreter(),
+		}
+
+		for name, m := range machines {
+			comp := compiler.NewCompiler()
+			for n, f := range testNatives {
+				comp.RegisterNative(n)
+				m.RegisterNative(n, f)
+			}
+
+			block, err := comp.CompileCode("testcase", []byte(c.code))
+			if err != nil {
+				t.Fatalf("compiler error:\n%s", err)
+			}
+
+			page := comp.Link(block)
+			m.LoadCodePage(page)
+			if _, err := m.Run(page.Main().Func(nil)); err != nil {
+				t.Fatalf("%s error: %s", name, err)
+			}
+
+			checkVMStackTop(t, name, m, c.stack)
+			checkVMRegisters(t, name, m, c.registers)
+		}
+	}
+}
+
+func TestNativeFunction(t *testing.T) {
+	tests := []vmTest{
+		{
+			`let q, r = divmod(7, 2); [q, r]`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(3),
+				object.NewInteger(1),
+			})),
+			assertRegister(),
+		},
+		{
+			`divmod(9, 4)`,
+			stack(object.NewInteger(1), object.NewInteger(2)),
+			assertRegister(),
+		},
+		{
+			`let f = fn(a, b) { a * b }; apply(f, 6, 7)`,
+			stack(object.NewInteger(42)),
+			assertRegister(),
+		},
+		{
+			`let f = fn(x) { let q, r = divmod(x, 3); q + r }; apply(f, 8)`,
+			stack(object.NewInteger(4)),
+			assertRegister(),
+		},
+		{
+			`let less = fn(a, b) { a < b }; sort([3, 1, 2], less)`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(2),
+				object.NewInteger(3),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Functions called by native function may call native functions.
+			text(
+				`let by = fn(a, b) { let [x] = sort([a[0], b[0]], fn(p, q) { p < q }); x == a[0] };`,
+				`let a = sort([[3], [1], [2]], by);`,
+				`[a[0][0], a[1][0], a[2][0]]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(2),
+				object.NewInteger(3),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Exception caught in the function called by native function.
+			text(
+				`let less = fn(a, b) { try { throw a } catch (e) { e < b } };`,
+				`1, sort([2, 1], less), 3`,
+			),
+			stack(
+				object.NewInteger(3),
+				object.NewArray([]object.Object{object.NewInteger(1), object.NewInteger(2)}),
+				object.NewInteger(1),
+			),
+			assertRegister(sp(3), bp(0)),
+		},
+		{
+			// Exception thrown in the function called by native function.
+			text(
+				`let less = fn(a, b) { throw "no order" };`,
+				`let ok, e = pcall(sort, [2, 1], less);`,
+				`[ok, e, sort([2, 1], fn(a, b) { a < b })]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewString("no order"),
+				object.NewArray([]object.Object{object.NewInteger(1), object.NewInteger(2)}),
+			})),
+			assertRegister(),
+		},
+		{
+			`let ok, e = pcall(fail, 1, 2); [ok, e["message"]]`,
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(false),
+				object.NewString("fail with 2 arguments"),
+			})),
+			assertRegister(),
+		},
+		{
+			`let ok, q, r = pcall(divmod, 5, 3); [ok, q, r]`,
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewInteger(1),
+				object.NewInteger(2),
+			})),
+			assertRegister(),
+		},
+		{
+			`let divmod = 3; divmod`,
+			stack(object.NewInteger(3)),
+			assertRegister(),
+		},
+		{
+			`let f = divmod; f == divmod`,
+			stack(object.NewBoolean(true)),
+			assertRegister(),
+		},
+		{
+			`let c = counter(); c::incr(); c::incr()::incr(); [c[0], typeof(c), c == c, c == counter()]`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(3),
+				object.NewString("USERVALUE"),
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+			})),
+			assertRegister(),
+		},
+		{
+			`[nothing(), 1]`,
+			stack(object.NewArray([]object.Object{
+				object.NewNull(),
+				object.NewInteger(1),
+			})),
+			assertRegister(),
+		},
+	}
+
+	runNativeTest(t, tests)
+}
+
+func TestNativeFunctionError(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{
+			`fail()`,
+			"fail with 0 arguments",
+		},
+		{
+			`let f = fn() { throw "inner" }; apply(f)`,
+			"uncaught exception: inner",
+		},
+		{
+			`counter() + 1`,
+			"USERVALUE PLUS(+) INTEGER is not accepted",
+		},
+		{
+			`counter()::decr()`,
+			"USERVALUE has no method decr",
+		},
+		{
+			`missing()`,
+			"native function missing is not registered",
+		},
+	}
+
+	for _, c := range tests {
+		machines := map[string]VM{
+			"vme": NewNaiveVM(),
+			"vmi": NewNaiveVMInterpreter(),
+		}
+
+		for name, m := range machines {
+			comp := compiler.NewCompiler()
+			comp.RegisterNative("fail", "apply", "counter", "missing")
+			m.RegisterNative("fail", testNatives["fail"])
+			m.RegisterNative("apply", testNatives["apply"])
+			m.RegisterNative("counter", testNatives["counter"])
+
+			block, err := comp.CompileCode("testcase", []byte(c.code))
+			if err != nil {
+				t.Fatalf("compiler error:\n%s", err)
+			}
+
+			page := comp.Link(block)
+			m.LoadCodePage(page)
+			_, err = m.Run(page.Main().Func(nil))
+			if err == nil {
+				t.Fatalf("[%s] expect error in code: %s", name, c.code)
+			}
+
+			if !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("[%s] wrong error, expect %q, got:\n%s", name, c.expected, err)
+			}
+		}
+	}
+}
+
+func TestCallFromHost(t *testing.T) {
+	code := text(
+		`let count = fn(n) { if (n < 0) { throw "negative" }; n + 1 };`,
+		`[count, fn(a, b) { return b, a }]`,
+	)
+
+	page := testCompileCode(t, code)
+	machines := map[string]VM{
+		"vme": NewNaiveVM(),
+		"vmi": NewNaiveVMInterpreter(),
+	}
+
+	for name, m := range machines {
+		m.LoadCodePage(page)
+		result, err := m.Run(page.Main().Func(nil))
+		if err != nil {
+			t.Fatalf("[%s] run error: %s", name, err)
+		}
+
+		functions := result[0].(*object.ArrayObject).Elements
+		count := functions[0].(*object.FunctionObject)
+		swap := functions[1].(*object.FunctionObject)
+		sp, bp := m.GetRegister("sp"), m.GetRegister("bp")
+
+		got, err := m.Call(count, object.NewInteger(41))
+		if err != nil || len(got) != 1 || !got[0].EqualTo(object.NewInteger(42)) {
+			t.Errorf("[%s] count(41) got %v, %v", name, got, err)
+		}
+
+		_, err = m.Call(count, object.NewInteger(-1))
+		if err == nil || !strings.Contains(err.Error(), "uncaught exception: negative") {
+			t.Errorf("[%s] count(-1) got error %v", name, err)
+		}
+
+		got, err = m.Call(swap, object.NewInteger(1), object.NewInteger(2))
+		if err != nil || len(got) != 2 ||
+			!got[0].EqualTo(object.NewInteger(2)) || !got[1].EqualTo(object.NewInteger(1)) {
+			t.Errorf("[%s] swap(1, 2) got %v, %v", name, got, err)
+		}
+
+		if _, err := m.Call(nil); err == nil {
+			t.Errorf("[%s] call of nil must fail", name)
+		}
+
+		if m.GetRegister("sp") != sp || m.GetRegister("bp") != bp {
+			t.Errorf("[%s] registers changed by call, sp %d -> %d, bp %d -> %d",
+				name, sp, m.GetRegister("sp"), bp, m.GetRegister("bp"))
+		}
+	}
+}
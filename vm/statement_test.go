@@ -27,6 +27,21 @@ func TestLetStatement(t *testing.T) {
 			stack(object.NewInteger(42)),
 			assertRegister(sp(1), bp(0)),
 		},
+		{
+			text(
+				`let empty? = fn(a) { a[0] == null };`,
+				`let first! = fn(a) { a[0] = 0 };`,
+				`let a = [1];`,
+				`[empty?([]), empty?(a), first!(a), a[0]!=1]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+				object.NewInteger(0),
+				object.NewBoolean(true),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
 	}
 
 	runVMTest(t, tests)
@@ -51,3 +66,510 @@ func TestLetStatement(t *testing.T) {
 
 // 	runVMTest(t, tests)
 // }
+
+func TestTailCall(t *testing.T) {
+	tests := []vmTest{
+		{
+			// Deeper than the call stack, works only with tail call.
+			text(
+				`let sum = fn(n, acc) {`,
+				`	if (n == 0) {`,
+				`		return acc;`,
+				`	}`,
+				`	fn(n - 1, acc + n)`,
+				`};`,
+				`sum(100000, 0)`,
+			),
+			stack(object.NewInteger(5000050000)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let count = fn(n) {`,
+				`	if (n > 0) {`,
+				`		let m = n - 1;`,
+				`		return fn(m);`,
+				`	} else {`,
+				`		"done"`,
+				`	}`,
+				`};`,
+				`count(200000)`,
+			),
+			stack(object.NewString("done")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Right operand of logical expression is in tail position.
+			text(
+				`let all = fn(n) { n == 0 || (n > 0 && fn(n - 1)) };`,
+				`[all(200000), all(-1)]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let sum = fn(n, acc) {`,
+				`	match (n) { 0 => acc, _ => fn(n - 1, acc + n) }`,
+				`};`,
+				`sum(100000, 0)`,
+			),
+			stack(object.NewInteger(5000050000)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let count = fn(n, ...rest) {`,
+				`	if (n == 0) { return rest; }`,
+				`	fn(...[n - 1], n)`,
+				`};`,
+				`count(200000)`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Tail call to function with different number of arguments.
+			text(
+				`let add = fn(a, b, c) { a + b + c };`,
+				`let inc = fn(x) { add(x, 1, 0) };`,
+				`inc(41), 1`,
+			),
+			stack(object.NewInteger(1), object.NewInteger(42)),
+			assertRegister(sp(2), bp(0)),
+		},
+		{
+			text(
+				`let adder = fn(n) { fn(x) { x + n } };`,
+				`let addTen = fn(x) { let ten = 10; adder(ten)(x) };`,
+				`[addTen(1), addTen(2)]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(11),
+				object.NewInteger(12),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestVarStatement(t *testing.T) {
+	tests := []vmTest{
+		{
+			`var x = 1; x = x + 1; x`,
+			stack(object.NewInteger(2)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`var a, b = 1; b = a = 5; [a, b]`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(5),
+				object.NewInteger(5),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn() { 1, 2 };`,
+				`var a, b = f();`,
+				`a = a + b;`,
+				`a`,
+			),
+			stack(object.NewInteger(3)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Closures capture the variable, rather than its value.
+			text(
+				`var n = 0;`,
+				`let incr = fn() { n = n + 1 };`,
+				`incr(); incr();`,
+				`n`,
+			),
+			stack(object.NewInteger(2)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let counter = fn() {`,
+				`  var n = 0;`,
+				`  fn() { n = n + 1 }`,
+				`};`,
+				`let c1 = counter();`,
+				`let c2 = counter();`,
+				`c1(); c1(); c2();`,
+				`[c1(), c2()]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(3),
+				object.NewInteger(2),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`var x = 1;`,
+				`let f = fn() { fn() { x = x * 10 } };`,
+				`f()();`,
+				`x`,
+			),
+			stack(object.NewInteger(10)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Variable bound through two functions, after another binding.
+			text(
+				`var f = 1;`,
+				`let a = fn(n) { fn() { n + f }() };`,
+				`a(2)`,
+			),
+			stack(object.NewInteger(3)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`var f = 1;`,
+				`let a = fn(n) { fn() { if (n > 0) { n + f } else { f } }() };`,
+				`[a(2), a(0)]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(3),
+				object.NewInteger(1),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestScopedVariable(t *testing.T) {
+	tests := []vmTest{
+		{
+			`let @a, $b = 1, 2; [@a, $b]`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(2),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`var @n = 0;`,
+				`let incr = fn() { @n = @n + 1 };`,
+				`incr(); incr();`,
+				`@n`,
+			),
+			stack(object.NewInteger(2)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Variables defined in functions live in module and global scope.
+			text(
+				`let init = fn(v) { var $total = v; let @step = 2; };`,
+				`let add = fn() { $total = $total + @step };`,
+				`init(10);`,
+				`add(); add()`,
+			),
+			stack(object.NewInteger(14)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Variables not stored yet are null.
+			text(
+				`let f = fn() { let @x = 3; };`,
+				`let g = fn() { @x };`,
+				`let first = g();`,
+				`f();`,
+				`[first, g()]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewNull(),
+				object.NewInteger(3),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestAssignIndex(t *testing.T) {
+	tests := []vmTest{
+		{
+			`let a = [1, 2, 3]; a[1] = 20; a`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(20),
+				object.NewInteger(3),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let h = {"a": 1};`,
+				`h.a = 10;`,
+				`h["b"] = h.a + 1;`,
+				`[h.a, h.b]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(10),
+				object.NewInteger(11),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`let a = [[0]]; a[0][0] = 5`,
+			stack(object.NewInteger(5)),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestWhileStatement(t *testing.T) {
+	tests := []vmTest{
+		{
+			`var i, sum = 0, 0; while (i < 5) { i = i + 1; sum = sum + i } sum`,
+			stack(object.NewInteger(15)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`while (false) { 1 } 2`,
+			stack(object.NewInteger(2)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`var i = 0;`,
+				`while (true) {`,
+				`  i = i + 1;`,
+				`  if (i >= 3) { break }`,
+				`}`,
+				`i`,
+			),
+			stack(object.NewInteger(3)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`var i, odd = 0, [];`,
+				`while (i < 6) {`,
+				`  i = i + 1;`,
+				`  if (i % 2 == 0) { continue }`,
+				`  odd = [odd, i]`,
+				`}`,
+				`odd`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewArray([]object.Object{
+					object.NewArray([]object.Object{
+						object.NewArray([]object.Object{}),
+						object.NewInteger(1),
+					}),
+					object.NewInteger(3),
+				}),
+				object.NewInteger(5),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn(n) {`,
+				`  var i = 0;`,
+				`  while (true) {`,
+				`    if (i == n) { return i * 10 }`,
+				`    i = i + 1`,
+				`  }`,
+				`};`,
+				`f(4)`,
+			),
+			stack(object.NewInteger(40)),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestForStatement(t *testing.T) {
+	tests := []vmTest{
+		{
+			`var sum = 0; for (x in [1, 2, 3]) { sum = sum + x } sum`,
+			stack(object.NewInteger(6)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`var sum = 0; for (i in 5) { sum = sum + i } sum`,
+			stack(object.NewInteger(10)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`var s = ""; for (k in {"a": 1, "b": 2}) { s = s + k } s`,
+			stack(object.NewString("ab")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`var s = ""; for (c in "abc") { s = c + s } s`,
+			stack(object.NewString("cba")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			`var s = ""; var n = 0; for (c in "héllo") { s = s + c; n = n + 1 } [s, n]`,
+			stack(object.NewArray([]object.Object{
+				object.NewString("héllo"),
+				object.NewInteger(6),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`var sum = 0;`,
+				`for (x in [1, 2, 3, 4, 5]) {`,
+				`  if (x == 2) { continue }`,
+				`  if (x == 4) { break }`,
+				`  sum = sum + x`,
+				`}`,
+				`sum`,
+			),
+			stack(object.NewInteger(4)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Break and continue leave the innermost loop.
+			text(
+				`var count = 0;`,
+				`for (i in 3) {`,
+				`  for (j in 3) {`,
+				`    if (j > i) { break }`,
+				`    count = count + 1`,
+				`  }`,
+				`}`,
+				`count`,
+			),
+			stack(object.NewInteger(6)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Closures capture value of loop variable in each iteration.
+			text(
+				`var fs = [];`,
+				`for (i in 3) { let f = fn() { i }; fs = [fs, f] }`,
+				`[fs[0][0][1](), fs[0][1](), fs[1]()]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(0),
+				object.NewInteger(1),
+				object.NewInteger(2),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Finally blocks are run when break leaves try blocks.
+			text(
+				`var log = "";`,
+				`for (i in 3) {`,
+				`  try {`,
+				`    if (i == 1) { break }`,
+				`    log = log + "t"`,
+				`  } finally {`,
+				`    log = log + "f"`,
+				`  }`,
+				`}`,
+				`try { throw 1 } catch (e) { log + "c" }`,
+			),
+			stack(object.NewString("tffc")),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let find = fn(a, v) {`,
+				`  for (x in a) { if (x == v) { return true } }`,
+				`  false`,
+				`};`,
+				`[find([1, 2], 2), find([1, 2], 3)]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestLetPattern(t *testing.T) {
+	tests := []vmTest{
+		{
+			`let [a, b, ...rest] = [1, 2, 3, 4]; [a, b, rest]`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(2),
+				object.NewArray([]object.Object{
+					object.NewInteger(3),
+					object.NewInteger(4),
+				}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			// Missing elements are null, and rest is empty.
+			`let [a, b, ...rest] = [1]; [a, b, rest]`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewNull(),
+				object.NewArray([]object.Object{}),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let person = {"name": "alice", "age": 30};`,
+				`let {name, age: years, email} = person;`,
+				`[name, years, email]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewString("alice"),
+				object.NewInteger(30),
+				object.NewNull(),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let f = fn() { [1, 2], 3 };`,
+				`var [x, y] = f();`,
+				`x = x + y;`,
+				`x`,
+			),
+			stack(object.NewInteger(3)),
+			assertRegister(sp(1), bp(0)),
+		},
+		{
+			text(
+				`let swap = fn(pair) {`,
+				`  let [a, b] = pair;`,
+				`  [b, a]`,
+				`};`,
+				`swap([1, 2])`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(2),
+				object.NewInteger(1),
+			})),
+			assertRegister(sp(1), bp(0)),
+		},
+	}
+
+	runVMTest(t, tests)
+}
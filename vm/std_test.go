@@ -0,0 +1,272 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flily/macaque-lang/object"
+)
+
+func ints(values ...int64) object.Object {
+	elements := make([]object.Object, len(values))
+	for i, v := range values {
+		elements[i] = object.NewInteger(v)
+	}
+
+	return object.NewArray(elements)
+}
+
+func strs(values ...string) object.Object {
+	elements := make([]object.Object, len(values))
+	for i, v := range values {
+		elements[i] = object.NewString(v)
+	}
+
+	return object.NewArray(elements)
+}
+
+func TestStdStrings(t *testing.T) {
+	tests := []vmTest{
+		{
+			`import "std/strings"; strings.length("hello")`,
+			stack(object.NewInteger(5)),
+			assertRegister(),
+		},
+		{
+			`import "std/strings"; strings.split("a,b,c", ",")`,
+			stack(strs("a", "b", "c")),
+			assertRegister(),
+		},
+		{
+			`import "std/strings"; strings.join(["a", 1, "c"], "-")`,
+			stack(object.NewString("a-1-c")),
+			assertRegister(),
+		},
+		{
+			`import "std/strings"; [strings.trim("  ab "), strings.upper("ab"), strings.lower("AB")]`,
+			stack(strs("ab", "AB", "ab")),
+			assertRegister(),
+		},
+		{
+			`import "std/strings"; [strings.find("hello", "ll"), strings.find("hello", "x")]`,
+			stack(ints(2, -1)),
+			assertRegister(),
+		},
+		{
+			`import "std/strings"; let s = "a-b-a"; [strings.replace(s, "a", "c"), s]`,
+			stack(strs("c-b-c", "a-b-a")),
+			assertRegister(),
+		},
+		{
+			`import s "std/strings"; let f = fn(g) { g("x") }; f(s.upper)`,
+			stack(object.NewString("X")),
+			assertRegister(),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestStdMath(t *testing.T) {
+	tests := []vmTest{
+		{
+			`import "std/math"; [math.abs(-3), math.abs(2.5), math.abs(-2.5)]`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(3),
+				object.NewFloat(2.5),
+				object.NewFloat(2.5),
+			})),
+			assertRegister(),
+		},
+		{
+			`import "std/math"; [math.min(3, 1.5, 2), math.max(3, 1.5, 2), math.max(7)]`,
+			stack(object.NewArray([]object.Object{
+				object.NewFloat(1.5),
+				object.NewInteger(3),
+				object.NewInteger(7),
+			})),
+			assertRegister(),
+		},
+		{
+			`import "std/math"; [math.floor(2.7), math.floor(-2.5), math.floor(3)]`,
+			stack(ints(2, -3, 3)),
+			assertRegister(),
+		},
+		{
+			`import "std/math"; [math.pow(2, 10), math.sqrt(16)]`,
+			stack(object.NewArray([]object.Object{
+				object.NewFloat(1024),
+				object.NewFloat(4),
+			})),
+			assertRegister(),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestStdArrays(t *testing.T) {
+	tests := []vmTest{
+		{
+			`import "std/arrays"; let a = [1, 2, 3]; [arrays.first(a), arrays.last(a), arrays.rest(a)]`,
+			stack(object.NewArray([]object.Object{
+				object.NewInteger(1),
+				object.NewInteger(3),
+				ints(2, 3),
+			})),
+			assertRegister(),
+		},
+		{
+			`import "std/arrays"; [arrays.first([]), arrays.last([]), arrays.rest([])]`,
+			stack(object.NewArray([]object.Object{
+				object.NewNull(),
+				object.NewNull(),
+				object.NewNull(),
+			})),
+			assertRegister(),
+		},
+		{
+			`import "std/arrays"; let a = [1]; let b = arrays.push(a, 2, 3); [a, b]`,
+			stack(object.NewArray([]object.Object{
+				ints(1),
+				ints(1, 2, 3),
+			})),
+			assertRegister(),
+		},
+		{
+			text(
+				`import "std/arrays";`,
+				`let a = [1, 2, 3, 4];`,
+				`let b = arrays.map(a, fn(x) { x * x });`,
+				`let c = arrays.filter(b, fn(x) { x % 2 == 0 });`,
+				`[a, b, c, arrays.reduce(a, fn(acc, x) { acc + x }, 0)]`,
+			),
+			stack(object.NewArray([]object.Object{
+				ints(1, 2, 3, 4),
+				ints(1, 4, 9, 16),
+				ints(4, 16),
+				object.NewInteger(10),
+			})),
+			assertRegister(),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestStdHashes(t *testing.T) {
+	tests := []vmTest{
+		{
+			`import "std/hashes"; let h = {"a": 1, "b": 2}; [hashes.keys(h), hashes.values(h)]`,
+			stack(object.NewArray([]object.Object{
+				strs("a", "b"),
+				ints(1, 2),
+			})),
+			assertRegister(),
+		},
+		{
+			`import "std/hashes"; let h = {"a": 1}; [hashes.has(h, "a"), hashes.has(h, "b"), hashes.has(h, [])]`,
+			stack(object.NewArray([]object.Object{
+				object.NewBoolean(true),
+				object.NewBoolean(false),
+				object.NewBoolean(false),
+			})),
+			assertRegister(),
+		},
+		{
+			`import "std/hashes"; let a = {"a": 1, "b": 2}; let m = hashes.merge(a, {"b": 3, "c": 4}); [a, m]`,
+			stack(object.NewArray([]object.Object{
+				object.NewHash([]object.HashPair{
+					{Key: object.NewString("a"), Value: object.NewInteger(1)},
+					{Key: object.NewString("b"), Value: object.NewInteger(2)},
+				}),
+				object.NewHash([]object.HashPair{
+					{Key: object.NewString("a"), Value: object.NewInteger(1)},
+					{Key: object.NewString("b"), Value: object.NewInteger(3)},
+					{Key: object.NewString("c"), Value: object.NewInteger(4)},
+				}),
+			})),
+			assertRegister(),
+		},
+		{
+			text(
+				`import "std/hashes";`,
+				`let h = {"a": 1, "b": 2, "c": 3};`,
+				`let m = hashes.map(h, fn(k, v) { k + v::to_string() });`,
+				`let f = hashes.filter(h, fn(k, v) { v != 2 });`,
+				`[m, f, hashes.reduce(h, fn(acc, k, v) { acc + v }, 0)]`,
+			),
+			stack(object.NewArray([]object.Object{
+				object.NewHash([]object.HashPair{
+					{Key: object.NewString("a"), Value: object.NewString("a1")},
+					{Key: object.NewString("b"), Value: object.NewString("b2")},
+					{Key: object.NewString("c"), Value: object.NewString("c3")},
+				}),
+				object.NewHash([]object.HashPair{
+					{Key: object.NewString("a"), Value: object.NewInteger(1)},
+					{Key: object.NewString("c"), Value: object.NewInteger(3)},
+				}),
+				object.NewInteger(6),
+			})),
+			assertRegister(),
+		},
+	}
+
+	runVMTest(t, tests)
+}
+
+func TestStdError(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{
+			`import "std/strings"; strings.length(1)`,
+			"length requires a STRING as argument 1, but got INTEGER",
+		},
+		{
+			`import "std/strings"; strings.split("a")`,
+			"split requires 2 arguments, but got 1",
+		},
+		{
+			`import "std/math"; math.max()`,
+			"max requires at least 1 argument",
+		},
+		{
+			`import "std/math"; math.sqrt("4")`,
+			"sqrt requires a number as argument 1, but got STRING",
+		},
+		{
+			`import "std/arrays"; arrays.map({}, fn(x) { x })`,
+			"map requires an ARRAY as argument 1, but got HASH",
+		},
+		{
+			`import "std/arrays"; arrays.map([1], fn(x) { throw "bad" })`,
+			"uncaught exception: bad",
+		},
+		{
+			`import "std/hashes"; hashes.merge({}, [])`,
+			"merge requires a HASH as argument 2, but got ARRAY",
+		},
+	}
+
+	for _, c := range tests {
+		page := testCompileCode(t, c.code)
+		machines := map[string]VM{
+			"vme": NewNaiveVM(),
+			"vmi": NewNaiveVMInterpreter(),
+		}
+
+		for name, m := range machines {
+			m.LoadCodePage(page)
+			_, err := m.Run(page.Main().Func(nil))
+			if err == nil {
+				t.Fatalf("[%s] expect error in code: %s", name, c.code)
+			}
+
+			if !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("[%s] wrong error, expect %q, got:\n%s", name, c.expected, err)
+			}
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package vm
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/flily/macaque-lang/compiler"
@@ -13,6 +16,11 @@ import (
 const (
 	DefaultStackSize = 65536
 	DefaultDataSize  = 65536
+
+	// StackReserved is number of slots left on each stack when a function is
+	// called, for values pushed inside the frame, and for message handler of
+	// xpcall running on stack overflow.
+	StackReserved = 256
 )
 
 var (
@@ -24,14 +32,33 @@ type callStackInfo struct {
 	ip  uint64
 	fi  uint64
 	fp  uint64
+	mi  uint64
 	sb  uint64
 	sp  uint64
 	ssi uint64
 }
 
+const (
+	tryKindCatch     = iota // try statement
+	tryKindProtected        // protected call by pcall and xpcall
+)
+
+// tryInfo is an exception handler, with registers to restore when an
+// exception is caught. ip is the address of the handler, or the address after
+// the protected call.
+type tryInfo struct {
+	callStackInfo
+	csi     uint64
+	kind    int
+	handler *object.FunctionObject // message handler of xpcall
+	mcsi    uint64                 // csi when message handler returns
+}
+
 type scopeInfo struct {
-	sp uint64
-	sb uint64
+	sp   uint64
+	sb   uint64
+	loop bool   // scope of a loop body, entered by LOOPIN
+	exit uint64 // address to leave the loop
 }
 
 type VM interface {
@@ -41,6 +68,11 @@ type VM interface {
 	GetStackObject(i int) object.Object
 	GetRegister(name string) uint64
 	Run(entry *object.FunctionObject, args ...object.Object) ([]object.Object, error)
+	RegisterNative(name string, f NativeFunction)
+	SetOutput(w io.Writer)
+	SetInput(r io.Reader)
+	Call(fn *object.FunctionObject, args ...object.Object) ([]object.Object, error)
+	CallFunction(fn object.Object, args ...object.Object) ([]object.Object, error)
 	InspectStack() (string, string)
 }
 
@@ -53,15 +85,29 @@ type NaiveVMBase struct {
 	bp uint64 // base pointer
 	fi uint64 // function index
 	fp uint64 // function pointer
+	mi uint64 // module index + 1, when running main function of a module
 
 	Stack      []object.Object
 	callStack  []callStackInfo
 	csi        uint64
 	scopeStack []scopeInfo
 	ssi        uint64
+	tryStack   []tryInfo
+	tsi        uint64
 	Functions  []*opcode.Function
+	Modules    []*opcode.Module
 	Result     []object.Object
 
+	moduleValues    []object.Object
+	moduleVariables [][]object.Object // indexed by module index + 1
+	globals         []object.Object
+
+	NativeNames []string // names of native functions in code page
+	natives     map[string]*NativeFunctionObject
+	self        VM // VM embedding this base, passed to native code
+	output      io.Writer
+	input       *bufio.Reader
+
 	AX int64
 }
 
@@ -71,8 +117,12 @@ func NewNaiveVMBase() *NaiveVMBase {
 		Stack:      make([]object.Object, DefaultStackSize),
 		callStack:  make([]callStackInfo, DefaultStackSize),
 		scopeStack: make([]scopeInfo, DefaultStackSize*4),
+		tryStack:   make([]tryInfo, DefaultStackSize),
+		output:     os.Stdout,
+		input:      bufio.NewReader(os.Stdin),
 	}
 
+	m.registerStandardLibrary()
 	return m
 }
 
@@ -149,12 +199,83 @@ func (m *NaiveVMBase) refData(i uint64) object.Object {
 	return m.Data[i]
 }
 
+// currentModule returns index + 1 of the module where running function is
+// defined, ZERO for the main program.
+func (m *NaiveVMBase) currentModule() int {
+	if f, ok := m.GetFunctionInfo(int(m.fi)); ok && f != nil {
+		return f.Module
+	}
+
+	return 0
+}
+
+// moduleRead reads variable i of current module, variables never stored are
+// null.
+func (m *NaiveVMBase) moduleRead(i int) object.Object {
+	mi := m.currentModule()
+	if mi >= len(m.moduleVariables) {
+		return null
+	}
+
+	return readVariable(m.moduleVariables[mi], i)
+}
+
+func (m *NaiveVMBase) moduleBind(i int, o object.Object) {
+	mi := m.currentModule()
+	for len(m.moduleVariables) <= mi {
+		m.moduleVariables = append(m.moduleVariables, nil)
+	}
+
+	m.moduleVariables[mi] = bindVariable(m.moduleVariables[mi], i, o)
+}
+
+func (m *NaiveVMBase) globalRead(i int) object.Object {
+	return readVariable(m.globals, i)
+}
+
+func (m *NaiveVMBase) globalBind(i int, o object.Object) {
+	m.globals = bindVariable(m.globals, i, o)
+}
+
+func readVariable(area []object.Object, i int) object.Object {
+	if i < 0 || i >= len(area) {
+		return null
+	}
+
+	return area[i]
+}
+
+// bindVariable sets variable i in data area, the area grows on demand.
+func bindVariable(area []object.Object, i int, o object.Object) []object.Object {
+	for len(area) <= i {
+		area = append(area, null)
+	}
+
+	area[i] = o
+	return area
+}
+
 func (m *NaiveVMBase) pushScope() {
 	m.scopeStack[m.ssi].sp = m.sp
 	m.scopeStack[m.ssi].sb = m.sb
+	m.scopeStack[m.ssi].loop = false
 	m.ssi++
 }
 
+// leaveLoop pops all scopes in the loop body, values in them are dropped.
+// The address to leave the loop is returned.
+func (m *NaiveVMBase) leaveLoop() uint64 {
+	for m.ssi > 0 {
+		info := m.scopeStack[m.ssi-1]
+		m.popScope()
+		if info.loop {
+			return info.exit
+		}
+	}
+
+	return m.ip
+}
+
 func (m *NaiveVMBase) popScope() bool {
 	if m.ssi == 0 {
 		return false
@@ -166,11 +287,25 @@ func (m *NaiveVMBase) popScope() bool {
 	return true
 }
 
+// checkStack returns a runtime error if there is no room on stacks for a new
+// frame of frameSize, instead of panic on stack overflow.
+func (m *NaiveVMBase) checkStack(frameSize int) error {
+	if m.sp+uint64(frameSize)+StackReserved >= uint64(len(m.Stack)) ||
+		m.csi+StackReserved >= uint64(len(m.callStack)) ||
+		m.ssi+StackReserved >= uint64(len(m.scopeStack)) ||
+		m.tsi+StackReserved >= uint64(len(m.tryStack)) {
+		return NewRuntimeError("stack overflow")
+	}
+
+	return nil
+}
+
 func (m *NaiveVMBase) pushCallInfo() {
 	m.callStack[m.csi].bp = m.bp
 	m.callStack[m.csi].ip = m.ip
 	m.callStack[m.csi].fi = m.fi
 	m.callStack[m.csi].fp = m.fp
+	m.callStack[m.csi].mi = m.mi
 	m.callStack[m.csi].sb = m.sb
 	m.callStack[m.csi].sp = m.sp
 	m.callStack[m.csi].ssi = m.ssi
@@ -187,12 +322,284 @@ func (m *NaiveVMBase) popCallInfo() bool {
 	m.ip = m.callStack[m.csi].ip
 	m.fi = m.callStack[m.csi].fi
 	m.fp = m.callStack[m.csi].fp
+	m.mi = m.callStack[m.csi].mi
 	m.sb = m.callStack[m.csi].sb
 	m.sp = m.callStack[m.csi].sp
 	m.ssi = m.callStack[m.csi].ssi
 	return true
 }
 
+func (m *NaiveVMBase) pushTryInfo(handler uint64) *tryInfo {
+	t := &m.tryStack[m.tsi]
+	t.bp = m.bp
+	t.ip = handler
+	t.fi = m.fi
+	t.fp = m.fp
+	t.mi = m.mi
+	t.sb = m.sb
+	t.sp = m.sp
+	t.ssi = m.ssi
+	t.csi = m.csi
+	t.kind = tryKindCatch
+	t.handler = nil
+	t.mcsi = 0
+	m.tsi++
+	return t
+}
+
+func (m *NaiveVMBase) topTryInfo() *tryInfo {
+	if m.tsi == 0 {
+		return nil
+	}
+
+	return &m.tryStack[m.tsi-1]
+}
+
+func (m *NaiveVMBase) popTryInfo() bool {
+	if m.tsi == 0 {
+		return false
+	}
+
+	m.tsi--
+	return true
+}
+
+// dropTryInfo removes handlers installed in function of call stack csi and
+// functions called by it.
+func (m *NaiveVMBase) dropTryInfo(csi uint64) {
+	for m.tsi > 0 && m.tryStack[m.tsi-1].csi >= csi {
+		m.tsi--
+	}
+}
+
+// catch unwinds stacks to the nearest exception handler and pushes the caught
+// value onto the stack. It returns false if there is no handler.
+func (m *NaiveVMBase) catch(err error) bool {
+	e, ok := err.(*RuntimeError)
+	if !ok || m.tsi == 0 {
+		return false
+	}
+
+	m.tsi--
+	t := &m.tryStack[m.tsi]
+	for i := t.sp; i < m.sp; i++ {
+		m.Stack[i] = nil
+	}
+
+	m.bp = t.bp
+	m.ip = t.ip
+	m.fi = t.fi
+	m.fp = t.fp
+	m.mi = t.mi
+	m.sb = t.sb
+	m.sp = t.sp
+	m.ssi = t.ssi
+	m.csi = t.csi
+	if t.kind == tryKindProtected {
+		m.stackPush(object.NewBoolean(false))
+	}
+
+	m.stackPush(e.Object())
+	return true
+}
+
+// protectedCall calls the function on top of the stack with n-1 arguments, in
+// protected mode. Any exception raised in the call is caught, and the call
+// returns false and the exception value, otherwise true and its return values.
+func (m *NaiveVMBase) protectedCall(n int, handler *object.FunctionObject) error {
+	if fn, ok := m.Top().(*object.FunctionObject); ok {
+		if err := m.checkStack(fn.FrameSize); err != nil {
+			return err
+		}
+	}
+
+	t := m.pushTryInfo(m.ip)
+	t.sp = m.sp - uint64(n)
+	t.kind = tryKindProtected
+	t.handler = handler
+
+	switch fn := m.Top().(type) {
+	case *object.FunctionObject:
+		m.StartFunctionCall(fn, n-1)
+		return nil
+
+	case *object.MethodObject, *NativeFunctionObject:
+		m.tsi--
+		return m.protectedCallNative(fn, n-1, handler)
+	}
+
+	return NewRuntimeError("%s is not callable", m.Top().Type())
+}
+
+// protectedCallNative calls native fn with n arguments in protected mode. As
+// native code runs at once, its error is caught here instead of unwinding.
+func (m *NaiveVMBase) protectedCallNative(fn object.Object, n int, handler *object.FunctionObject) error {
+	m.stackPop()
+	args := m.popArguments(n)
+	result, err := m.callNative(fn, args)
+	if err == nil {
+		m.stackPush(object.NewBoolean(true))
+		m.stackPushN(result)
+		return nil
+	}
+
+	e, ok := err.(*RuntimeError)
+	if !ok {
+		return err
+	}
+
+	value := e.Object()
+	if handler != nil {
+		r, err := m.self.CallFunction(handler, value)
+		if err != nil {
+			return err
+		}
+
+		value = null
+		if len(r) > 0 {
+			value = r[0]
+		}
+	}
+
+	m.stackPush(object.NewBoolean(false))
+	m.stackPush(value)
+	return nil
+}
+
+// finishProtectedCall pushes true before return values, if a protected call
+// returns normally.
+func (m *NaiveVMBase) finishProtectedCall() {
+	t := m.topTryInfo()
+	if t == nil || t.kind != tryKindProtected || t.mcsi > 0 || t.csi != m.csi {
+		return
+	}
+
+	m.tsi--
+	m.stackPopN(m.sp - t.sp)
+	m.stackPush(object.NewBoolean(true))
+}
+
+// startMessageHandler calls message handler of xpcall with exception value,
+// before stacks are unwound. It returns false if there is no message handler.
+func (m *NaiveVMBase) startMessageHandler(err error) bool {
+	e, ok := err.(*RuntimeError)
+	t := m.topTryInfo()
+	if !ok || t == nil || t.kind != tryKindProtected || t.handler == nil || t.mcsi > 0 {
+		return false
+	}
+
+	t.mcsi = m.csi
+	m.stackPush(e.Object())
+	m.stackPush(t.handler)
+	m.StartFunctionCall(t.handler, 1)
+	return true
+}
+
+// finishMessageHandler turns return value of message handler to an exception,
+// which is caught by the protected call.
+func (m *NaiveVMBase) finishMessageHandler(values []object.Object) error {
+	t := m.topTryInfo()
+	if t == nil || t.kind != tryKindProtected || t.mcsi == 0 || t.mcsi != m.csi {
+		return nil
+	}
+
+	e := NewThrowError(null)
+	if len(values) > 0 {
+		e = NewThrowError(values[0])
+	}
+
+	return e
+}
+
+// position returns source of the instruction before ip in function fi.
+func (m *NaiveVMBase) position(fi uint64, fp uint64, ip uint64) *token.Context {
+	f, ok := m.GetFunctionInfo(int(fi))
+	if !ok {
+		return nil
+	}
+
+	offset := int64(ip) - int64(fp) - 1
+	if offset < 0 || offset >= int64(len(f.DebugInfo)) {
+		return nil
+	}
+
+	return f.DebugInfo[offset]
+}
+
+// traceback returns positions of all functions in call stack, from the current
+// function to the entry.
+func (m *NaiveVMBase) traceback() object.Object {
+	frames := make([]object.Object, 0, m.csi)
+	add := func(fi uint64, fp uint64, ip uint64) {
+		ctx := m.position(fi, fp, ip)
+		if ctx == nil || len(ctx.Tokens) == 0 {
+			return
+		}
+
+		first := ctx.Tokens[0]
+		s := fmt.Sprintf("%s:%d:%d", first.Filename(), first.LineNo(), first.ColumnStart())
+		frames = append(frames, object.NewString(s))
+	}
+
+	add(m.fi, m.fp, m.ip)
+	// callStack[0] is registers before the entry function.
+	for i := int(m.csi) - 1; i > 0; i-- {
+		info := &m.callStack[i]
+		add(info.fi, info.fp, info.ip)
+	}
+
+	return object.NewArray(frames)
+}
+
+// funcInfo makes a hash of information of function o, with number of arguments,
+// whether it has a rest parameter, and source position where the code of
+// function starts. Position is null if debug information is missing.
+func (m *NaiveVMBase) funcInfo(o object.Object) (object.Object, error) {
+	// Native functions and methods take all arguments like fn(...args), and
+	// have no source position.
+	arguments, variadic := 1, true
+	var file, line, column object.Object = null, null, null
+
+	switch f := o.(type) {
+	case *object.FunctionObject:
+		arguments, variadic = f.Arguments, f.Variadic
+		info, ok := m.GetFunctionInfo(int(f.Index))
+		if ok && info != nil && len(info.DebugInfo) > 0 {
+			if ctx := info.DebugInfo[0]; ctx != nil && len(ctx.Tokens) > 0 {
+				first := ctx.Tokens[0]
+				file = object.NewString(first.Filename())
+				line = object.NewInteger(int64(first.LineNo()))
+				column = object.NewInteger(int64(first.ColumnStart()))
+			}
+		}
+
+	case *object.MethodObject, *NativeFunctionObject:
+
+	default:
+		return nil, NewRuntimeError("%s is not a function", o.Type())
+	}
+
+	pairs := []object.HashPair{
+		{Key: object.NewString("arguments"), Value: object.NewInteger(int64(arguments))},
+		{Key: object.NewString("variadic"), Value: object.NewBoolean(variadic)},
+		{Key: object.NewString("file"), Value: file},
+		{Key: object.NewString("line"), Value: line},
+		{Key: object.NewString("column"), Value: column},
+	}
+
+	return object.NewHash(pairs), nil
+}
+
+// setErrorContext attaches source of current instruction to runtime error.
+func (m *NaiveVMBase) setErrorContext(err error) {
+	e, ok := err.(*RuntimeError)
+	if !ok || e.Context != nil {
+		return
+	}
+
+	e.Context = m.position(m.fi, m.fp, m.ip)
+}
+
 func (m *NaiveVMBase) initCallStack(frameSize int) {
 	m.bp = m.sp - 1
 	for i := 0; i < frameSize; i++ {
@@ -225,6 +632,9 @@ func (m *NaiveVMBase) GetRegister(name string) uint64 {
 
 	case "fp":
 		r = m.fp
+
+	case "mi":
+		r = m.mi
 	}
 
 	return r
@@ -270,6 +680,45 @@ func (m *NaiveVMBase) SetEntry(entry *object.FunctionObject) {
 	m.sb = m.sp
 }
 
+func (m *NaiveVMBase) loadNatives(page *opcode.CodePage) {
+	m.NativeNames = make([]string, len(page.Natives))
+	copy(m.NativeNames, page.Natives)
+}
+
+func (m *NaiveVMBase) loadModules(page *opcode.CodePage) {
+	m.Modules = make([]*opcode.Module, len(page.NativeModules))
+	copy(m.Modules, page.NativeModules)
+	m.moduleValues = make([]object.Object, len(m.Modules))
+}
+
+func (m *NaiveVMBase) addModule(module *opcode.Module) {
+	m.Modules = append(m.Modules, module)
+	m.moduleValues = append(m.moduleValues, nil)
+}
+
+// importModule pushes value of module onto the stack. Main function of module
+// is called at the first time of import, and the value is saved when returns.
+func (m *NaiveVMBase) importModule(index int) error {
+	if index < 0 || index >= len(m.Modules) {
+		return NewRuntimeError("module %d not found", index)
+	}
+
+	if value := m.moduleValues[index]; value != nil {
+		m.stackPush(value)
+		return nil
+	}
+
+	fn := m.Modules[index].Main().Func(nil)
+	if err := m.checkStack(fn.FrameSize); err != nil {
+		return err
+	}
+
+	m.stackPush(fn)
+	m.StartFunctionCall(fn, 0)
+	m.mi = uint64(index) + 1
+	return nil
+}
+
 func (m *NaiveVMBase) GetFunctionInfo(i int) (*opcode.Function, bool) {
 	if i < 0 || i >= len(m.Functions) {
 		return nil, false
@@ -278,6 +727,18 @@ func (m *NaiveVMBase) GetFunctionInfo(i int) (*opcode.Function, bool) {
 	return m.Functions[i], true
 }
 
+// isIntegerDivision checks whether operator t is division or modulo of two
+// integers, which panics in Go if right is zero.
+func isIntegerDivision(t token.Token, left object.Object, right object.Object) bool {
+	if t != token.Slash && t != token.Modulo {
+		return false
+	}
+
+	_, okLeft := left.(*object.IntegerObject)
+	_, okRight := right.(*object.IntegerObject)
+	return okLeft && okRight
+}
+
 func (m *NaiveVMBase) ExecOpcode(op opcode.Opcode) (error, bool) {
 	var e error
 	var isHalt bool
@@ -321,6 +782,29 @@ func (m *NaiveVMBase) ExecOpcode(op opcode.Opcode) (error, bool) {
 		o := m.refData(uint64(op.Operand0))
 		m.stackPush(o)
 
+	case opcode.INative:
+		f, err := m.loadNative(op.Operand0)
+		if err != nil {
+			e = err
+			break
+		}
+
+		m.stackPush(f)
+
+	case opcode.IMStore:
+		o := m.stackPop()
+		m.moduleBind(op.Operand0, o)
+
+	case opcode.IMLoad:
+		m.stackPush(m.moduleRead(op.Operand0))
+
+	case opcode.IGStore:
+		o := m.stackPop()
+		m.globalBind(op.Operand0, o)
+
+	case opcode.IGLoad:
+		m.stackPush(m.globalRead(op.Operand0))
+
 	case opcode.IPop:
 		m.stackPopN(uint64(op.Operand0))
 
@@ -336,6 +820,11 @@ func (m *NaiveVMBase) ExecOpcode(op opcode.Opcode) (error, bool) {
 		operator := token.Token(op.Operand0)
 		right := m.stackPop()
 		left := m.stackPop()
+		if isIntegerDivision(operator, left, right) && right.(*object.IntegerObject).Value == 0 {
+			e = NewRuntimeError("integer divided by zero")
+			break
+		}
+
 		o, ok := left.OnInfix(operator, right)
 		if !ok {
 			e = NewRuntimeError(
@@ -409,6 +898,80 @@ func (m *NaiveVMBase) ExecOpcode(op opcode.Opcode) (error, bool) {
 		}
 		m.stackPush(o)
 
+	case opcode.ISlice:
+		high := m.stackPop()
+		low := m.stackPop()
+		base := m.stackPop()
+		o, ok := base.OnSlice(low, high)
+		if !ok {
+			e = NewRuntimeError(
+				"%s[%s:%s] is not accepted", base.Type(), low.Type(), high.Type())
+			break
+		}
+		m.stackPush(o)
+
+	case opcode.IIsType:
+		o := m.stackPop()
+		m.stackPush(object.NewBoolean(o.Type() == object.ObjectType(op.Operand0)))
+
+	case opcode.ILen:
+		o := m.stackPop()
+		switch v := o.(type) {
+		case *object.ArrayObject:
+			m.stackPush(object.NewInteger(int64(len(v.Elements))))
+
+		case *object.HashObject:
+			m.stackPush(object.NewInteger(int64(len(v.Map))))
+
+		default:
+			e = NewRuntimeError("%s has no length", o.Type())
+		}
+
+	case opcode.IConcat:
+		values := m.stackPopNWithValue(op.Operand0)
+		var b strings.Builder
+		for _, v := range values {
+			b.WriteString(v.Inspect())
+		}
+		m.stackPush(object.NewString(b.String()))
+
+	case opcode.ISetIndex:
+		value := m.stackPop()
+		index := m.stackPop()
+		base := m.stackPop()
+		if !base.OnAssignIndex(index, value) {
+			e = NewRuntimeError(
+				"%s[%s] can not be assigned", base.Type(), index.Type())
+			break
+		}
+		m.stackPush(value)
+
+	case opcode.IMakeCell:
+		o := m.stackPop()
+		m.stackPush(object.NewCell(o))
+
+	case opcode.IDeref:
+		o := m.stackPop()
+		cell, ok := o.(*object.CellObject)
+		if !ok {
+			e = NewRuntimeError("%s is not a cell", o.Type())
+			break
+		}
+		m.stackPush(cell.Value)
+
+	case opcode.ISetRef:
+		o := m.stackPop()
+		value := m.stackPop()
+		cell, ok := o.(*object.CellObject)
+		if !ok {
+			e = NewRuntimeError("%s is not a cell", o.Type())
+			break
+		}
+		cell.Value = value
+
+	case opcode.IJump:
+		m.IncrIP(int64(op.Operand0))
+
 	case opcode.IJumpFWD:
 		m.incrIP(uint64(op.Operand0))
 
@@ -432,15 +995,114 @@ func (m *NaiveVMBase) ExecOpcode(op opcode.Opcode) (error, bool) {
 		m.stackPush(top)
 
 	case opcode.ICall:
-		f := m.Top()
-		if f.Type() != object.ObjectTypeFunction {
+		e = m.startCall(op.Operand0, m.StartFunctionCall)
+
+	case opcode.ICallV, opcode.ITailCallV:
+		size := m.StackScopeSize()
+		values := m.stackPopNWithValue(size)
+		m.popScope()
+		m.stackPushN(values)
+
+		start := m.StartFunctionCall
+		if op.Name == opcode.ITailCallV {
+			start = m.StartTailCall
+		}
+
+		e = m.startCall(size-1, start)
+
+	case opcode.IMethod:
+		name := m.refData(uint64(op.Operand0)).(*object.StringObject).Value
+		self := m.Top()
+		method, ok := self.OnMethod(name)
+		if !ok {
+			e = NewRuntimeError("%s has no method %s", self.Type(), name)
+			break
+		}
+
+		m.stackPush(method)
+
+	case opcode.ISpread:
+		o := m.stackPop()
+		array, ok := o.(*object.ArrayObject)
+		if !ok {
 			e = NewRuntimeError(
-				"%s is not callable", f.Type())
+				"%s can not be spread", o.Type())
+			break
+		}
+
+		// Arguments are pushed from right to left.
+		for i := len(array.Elements) - 1; i >= 0; i-- {
+			m.stackPush(array.Elements[i])
+		}
+
+	case opcode.ITailCall:
+		e = m.startCall(op.Operand0, m.StartTailCall)
+
+	case opcode.IImport:
+		e = m.importModule(op.Operand0)
+
+	case opcode.IPCall:
+		e = m.protectedCall(op.Operand0, nil)
+
+	case opcode.IXPCall:
+		f := m.stackPop()
+		o := m.stackPop()
+		handler, ok := o.(*object.FunctionObject)
+		if !ok {
+			e = NewRuntimeError("message handler %s is not callable", o.Type())
+			break
+		}
+
+		m.stackPush(f)
+		e = m.protectedCall(op.Operand0-1, handler)
+
+	case opcode.ITraceback:
+		m.stackPush(m.traceback())
+
+	case opcode.ITypeOf:
+		o := m.stackPop()
+		m.stackPush(object.NewString(o.Type().String()))
+
+	case opcode.IPrint, opcode.IPrintLn:
+		args := m.popArguments(op.Operand0)
+		if e = m.print(args, op.Name == opcode.IPrintLn); e == nil {
+			m.stackPush(null)
+		}
+
+	case opcode.IPrintf:
+		args := m.popArguments(op.Operand0)
+		if e = m.printf(args); e == nil {
+			m.stackPush(null)
+		}
+
+	case opcode.IReadLine:
+		line, err := m.readLine()
+		if err != nil {
+			e = err
 			break
 		}
 
-		fn := f.(*object.FunctionObject)
-		m.StartFunctionCall(fn)
+		m.stackPush(line)
+
+	case opcode.IFuncInfo:
+		o := m.stackPop()
+		info, err := m.funcInfo(o)
+		if err != nil {
+			e = err
+			break
+		}
+
+		m.stackPush(info)
+
+	case opcode.ITry:
+		m.pushTryInfo(m.ip + uint64(op.Operand0))
+
+	case opcode.IEndTry:
+		m.popTryInfo()
+
+	case opcode.IThrow:
+		o := m.stackPop()
+		e = NewThrowError(o)
 
 	case opcode.IScopeIn:
 		m.pushScope()
@@ -457,12 +1119,43 @@ func (m *NaiveVMBase) ExecOpcode(op opcode.Opcode) (error, bool) {
 		m.popScope()
 		m.stackPushN(values)
 
+	case opcode.ILoopIn:
+		m.pushScope()
+		m.sb = m.sp
+		m.scopeStack[m.ssi-1].loop = true
+		m.scopeStack[m.ssi-1].exit = m.ip + uint64(op.Operand0)
+
+	case opcode.IBreak:
+		m.ip = m.leaveLoop()
+
+	case opcode.IContinue:
+		// The instruction before exit jumps back to the head of loop.
+		m.ip = m.leaveLoop() - 1
+
+	case opcode.IIter:
+		o := m.stackPop()
+		iterator, ok := object.NewIterator(o)
+		if !ok {
+			e = NewRuntimeError("%s is not iterable", o.Type())
+			break
+		}
+		m.stackPush(iterator)
+
+	case opcode.INext:
+		iterator := m.Top().(*object.IteratorObject)
+		if o, ok := iterator.Next(); ok {
+			m.stackPush(o)
+		} else {
+			m.incrIP(uint64(op.Operand0))
+		}
+
 	case opcode.IClean:
 		n := m.sp - m.sb
 		m.stackPopN(n)
 
 	case opcode.IReturn:
-		m.FinishCall()
+		values := m.FinishCall()
+		e = m.finishMessageHandler(values)
 		if m.csi <= 0 {
 			isHalt = true
 		}
@@ -477,25 +1170,223 @@ func (m *NaiveVMBase) ExecOpcode(op opcode.Opcode) (error, bool) {
 	// 	fmt.Printf("      %s\n", vv)
 	// }
 
+	if e != nil {
+		m.setErrorContext(e)
+	}
+
 	return e, isHalt
 }
 
-func (m *NaiveVMBase) StartFunctionCall(fn *object.FunctionObject) {
+// prepareArguments adjusts n arguments under function fn on the stack to exactly
+// fn.Arguments values. Missing arguments are null, and extra arguments are
+// collected into an array as the rest parameter if fn is variadic, or dropped.
+func (m *NaiveVMBase) prepareArguments(fn *object.FunctionObject, n int) {
+	if !fn.Variadic && n == fn.Arguments {
+		return
+	}
+
+	values := m.stackPopNWithValue(n + 1)
+	params := fn.Arguments
+	if fn.Variadic {
+		params--
+	}
+
+	args := make([]object.Object, fn.Arguments)
+	for i := 0; i < params; i++ {
+		args[i] = null
+		if i < n {
+			args[i] = values[n-1-i]
+		}
+	}
+
+	if fn.Variadic {
+		rest := make([]object.Object, 0)
+		for i := params; i < n; i++ {
+			rest = append(rest, values[n-1-i])
+		}
+		args[params] = object.NewArray(rest)
+	}
+
+	for i := len(args) - 1; i >= 0; i-- {
+		m.stackPush(args[i])
+	}
+	m.stackPush(values[n])
+}
+
+// startCall calls the function on the top of stack with n arguments. Script
+// function is started by start, while native method is called at once, and its
+// return values are pushed onto the stack.
+func (m *NaiveVMBase) startCall(n int, start func(*object.FunctionObject, int)) error {
+	switch fn := m.Top().(type) {
+	case *object.FunctionObject:
+		if err := m.checkStack(fn.FrameSize); err != nil {
+			return err
+		}
+
+		start(fn, n)
+		return nil
+
+	case *object.MethodObject, *NativeFunctionObject:
+		m.stackPop()
+		args := m.popArguments(n)
+		result, err := m.callNative(fn, args)
+		if err != nil {
+			return err
+		}
+
+		m.stackPushN(result)
+		return nil
+	}
+
+	return NewRuntimeError("%s is not callable", m.Top().Type())
+}
+
+// callNative calls native method or native function fn with args. Errors of
+// native code are turned into runtime errors, while runtime errors of script
+// functions called by it are kept.
+func (m *NaiveVMBase) callNative(fn object.Object, args []object.Object) ([]object.Object, error) {
+	var result []object.Object
+	var err error
+	switch f := fn.(type) {
+	case *object.MethodObject:
+		result, err = f.Call(m.self, args)
+
+	case *NativeFunctionObject:
+		result, err = f.Function(m.self, args)
+
+	default:
+		err = NewRuntimeError("%s is not callable", fn.Type())
+	}
+
+	if err != nil {
+		if e, ok := err.(*RuntimeError); ok {
+			return nil, e
+		}
+
+		return nil, NewRuntimeError("%s", err)
+	}
+
+	return result, nil
+}
+
+// callState is the registers saved before a function is called from native
+// code, and restored if the function raises an exception.
+type callState struct {
+	callStackInfo
+	csi uint64
+	tsi uint64
+}
+
+func (m *NaiveVMBase) saveCallState() callState {
+	s := callState{csi: m.csi, tsi: m.tsi}
+	s.bp, s.ip, s.fi, s.fp, s.mi = m.bp, m.ip, m.fi, m.fp, m.mi
+	s.sb, s.sp, s.ssi = m.sb, m.sp, m.ssi
+	return s
+}
+
+func (m *NaiveVMBase) restoreCallState(s callState) {
+	for i := s.sp; i < m.sp; i++ {
+		m.Stack[i] = nil
+	}
+
+	m.bp, m.ip, m.fi, m.fp, m.mi = s.bp, s.ip, s.fi, s.fp, s.mi
+	m.sb, m.sp, m.ssi = s.sb, s.sp, s.ssi
+	m.csi, m.tsi = s.csi, s.tsi
+}
+
+// Call calls script function fn with args, and returns its return values. It is
+// safe to be called by native functions in the middle of a call, and stacks and
+// registers of VM are kept as before when it returns, even on error.
+func (m *NaiveVMBase) Call(fn *object.FunctionObject, args ...object.Object) ([]object.Object, error) {
+	if fn == nil {
+		return nil, NewRuntimeError("NULL is not callable")
+	}
+
+	return m.self.CallFunction(fn, args...)
+}
+
+// startNestedCall starts a call of fn with args from native code. Native method
+// is called at once, and its return values are returned with done set.
+func (m *NaiveVMBase) startNestedCall(fn object.Object, args []object.Object) ([]object.Object, bool, error) {
+	switch f := fn.(type) {
+	case *object.FunctionObject:
+		if err := m.checkStack(f.FrameSize + len(args)); err != nil {
+			return nil, true, err
+		}
+
+		m.StartCall(f, args...)
+		return nil, false, nil
+
+	case *object.MethodObject, *NativeFunctionObject:
+		result, err := m.callNative(f, args)
+		return result, true, err
+	}
+
+	return nil, true, NewRuntimeError("%s is not callable", fn.Type())
+}
+
+// finishNestedCall pops return values of a function called from native code.
+// On error, registers are restored to the state before the call.
+func (m *NaiveVMBase) finishNestedCall(s callState, err error) ([]object.Object, error) {
+	if err != nil {
+		m.restoreCallState(s)
+		return nil, err
+	}
+
+	result := make([]object.Object, len(m.Result))
+	copy(result, m.Result)
+	m.stackPopN(uint64(len(result)))
+	return result, nil
+}
+
+// StartFunctionCall calls function fn with n arguments on the stack.
+func (m *NaiveVMBase) StartFunctionCall(fn *object.FunctionObject, n int) {
+	m.prepareArguments(fn, n)
 	m.pushCallInfo()
 	m.pushScope()
 	m.initCallStack(fn.FrameSize)
 	m.fi = fn.Index
 	m.fp = fn.IP
 	m.ip = fn.IP
+	m.mi = 0 // module of caller is saved in call info
+}
+
+// StartTailCall calls function fn with n arguments on the stack, replacing the
+// frame of current function, so call stack and scope stack do not grow. The
+// call info of current function is kept, and then fn returns to its caller.
+func (m *NaiveVMBase) StartTailCall(fn *object.FunctionObject, n int) {
+	values := m.stackPopNWithValue(n + 1)
+
+	current := m.Stack[m.bp].(*object.FunctionObject)
+	base := m.bp - uint64(current.Arguments)
+	for i := base; i < m.sp; i++ {
+		m.Stack[i] = nil
+	}
+
+	m.dropTryInfo(m.csi)
+	caller := &m.callStack[m.csi-1]
+	m.sp = base
+	m.sb = caller.sb
+	m.ssi = caller.ssi
+	m.stackPushN(values)
+	m.prepareArguments(fn, n)
+	caller.sp = m.sp
+
+	m.pushScope()
+	m.initCallStack(fn.FrameSize)
+	m.fi = fn.Index
+	m.fp = fn.IP
+	m.ip = fn.IP
+	m.mi = 0
 }
 
 func (m *NaiveVMBase) StartCall(fn *object.FunctionObject, args ...object.Object) {
-	for _, arg := range args {
-		m.stackPush(arg)
+	for i := len(args) - 1; i >= 0; i-- {
+		m.stackPush(args[i])
 	}
 
 	m.stackPush(fn)
-	m.StartFunctionCall(fn)
+	m.StartFunctionCall(fn, len(args))
 }
 
 func (m *NaiveVMBase) FindReturnValueOnStack() ([]object.Object, int) {
@@ -513,12 +1404,25 @@ func (m *NaiveVMBase) FinishCall() []object.Object {
 	returnValues := m.stackPopNWithValue(n)
 	m.Result = returnValues
 
+	if m.mi > 0 {
+		// A module is exported as ONE value.
+		value := null
+		if n > 0 {
+			value = returnValues[0]
+		}
+
+		m.moduleValues[m.mi-1] = value
+		returnValues = []object.Object{value}
+	}
+
+	m.dropTryInfo(m.csi)
 	m.popCallInfo()
 	// m.popScope()
 	f := m.stackPop() // Pop this function object
 	fo := f.(*object.FunctionObject)
 
 	m.stackPopN(uint64(fo.Arguments))
+	m.finishProtectedCall()
 	m.stackPushN(returnValues)
 	return returnValues
 }
@@ -588,6 +1492,7 @@ func NewNaiveVM() *NaiveVM {
 		Code:        make([]opcode.Opcode, 0),
 	}
 
+	m.self = m
 	return m
 }
 
@@ -610,6 +1515,9 @@ func (m *NaiveVM) Run(entry *object.FunctionObject, args ...object.Object) ([]ob
 		// fmt.Printf("%s\n", info.Message("%s", op))
 
 		e, isHalt = m.ExecOpcode(op)
+		if e != nil && (m.startMessageHandler(e) || m.catch(e)) {
+			e = nil
+		}
 	}
 
 	// if m.csi > 0 {
@@ -620,6 +1528,28 @@ func (m *NaiveVM) Run(entry *object.FunctionObject, args ...object.Object) ([]ob
 	return result, e
 }
 
+// CallFunction calls fn with args from native code, and runs until fn returns.
+// Only exceptions caught by handlers installed in the call are handled here,
+// others are returned with registers restored.
+func (m *NaiveVM) CallFunction(fn object.Object, args ...object.Object) ([]object.Object, error) {
+	s := m.saveCallState()
+	result, done, e := m.startNestedCall(fn, args)
+	if done {
+		return result, e
+	}
+
+	codeSize := uint64(len(m.Code))
+	for m.csi > s.csi && m.ip < codeSize && e == nil {
+		op := m.fetchOp()
+		e, _ = m.ExecOpcode(op)
+		if e != nil && m.tsi > s.tsi && (m.startMessageHandler(e) || m.catch(e)) {
+			e = nil
+		}
+	}
+
+	return m.finishNestedCall(s, e)
+}
+
 func (m *NaiveVM) loadFunctions(page *opcode.CodePage) {
 	m.Functions = make([]*opcode.Function, len(page.Functions))
 	copy(m.Functions, page.Functions)
@@ -643,6 +1573,8 @@ func (m *NaiveVM) LoadCodePage(page *opcode.CodePage) {
 	m.loadFunctions(page)
 	m.loadCode(page)
 	m.loadData(page)
+	m.loadModules(page)
+	m.loadNatives(page)
 }
 
 type NaiveVMInterpreter struct {
@@ -655,6 +1587,7 @@ func NewNaiveVMInterpreter() *NaiveVMInterpreter {
 		NaiveVMBase: *NewNaiveVMBase(),
 	}
 
+	m.self = m
 	return m
 }
 
@@ -664,6 +1597,8 @@ func (i *NaiveVMInterpreter) LoadCodePage(page *opcode.CodePage) {
 	i.CodePage = page
 	i.Data = page.Data
 	i.Functions = page.Functions
+	i.loadModules(page)
+	i.loadNatives(page)
 }
 
 func (i *NaiveVMInterpreter) MergeCodeBlock(block *opcode.CodeBlock, ctx *compiler.CompilerContext) {
@@ -671,11 +1606,25 @@ func (i *NaiveVMInterpreter) MergeCodeBlock(block *opcode.CodeBlock, ctx *compil
 	main := page.Functions[0]
 	main.Append(block)
 
-	for j := len(page.Functions); j < len(ctx.Functions); j++ {
-		f := ctx.Functions[j]
-		f.Link()
-		page.Functions = append(page.Functions, f)
-		i.Functions = append(i.Functions, f)
+	// New functions are not linked yet, and get their global index here.
+	for _, f := range ctx.Functions[1:] {
+		if !f.IsLink() {
+			i.mergeFunction(f)
+		}
+	}
+
+	for j := len(page.NativeModules); j < len(ctx.Modules.Modules); j++ {
+		m := ctx.Modules.Modules[j]
+		page.AddModule(m)
+		i.addModule(m)
+		for _, f := range m.Functions {
+			i.mergeFunction(f)
+		}
+	}
+
+	for j := len(page.Natives); j < len(ctx.Natives.Names); j++ {
+		page.Natives = append(page.Natives, ctx.Natives.Names[j])
+		i.NativeNames = append(i.NativeNames, ctx.Natives.Names[j])
 	}
 
 	for j := len(page.Data); j < len(ctx.Literal.Values); j++ {
@@ -691,11 +1640,18 @@ func (i *NaiveVMInterpreter) MergeCodeBlock(block *opcode.CodeBlock, ctx *compil
 	main.FrameSize = newFrameSize
 }
 
+func (i *NaiveVMInterpreter) mergeFunction(f *opcode.Function) {
+	page := i.CodePage
+	f.GlobalIndex = uint64(len(page.Functions))
+	page.Functions = append(page.Functions, f)
+	i.Functions = page.Functions
+}
+
 func (i *NaiveVMInterpreter) getFunction(o object.Object) (*opcode.Function, error) {
 	f, ok := o.(*object.FunctionObject)
 	if !ok {
 		return nil, NewRuntimeError(
-			"%s is not callable", f.Type())
+			"%s is not callable", o.Type())
 	}
 
 	fn, ok := i.GetFunctionInfo(int(f.Index))
@@ -707,14 +1663,19 @@ func (i *NaiveVMInterpreter) getFunction(o object.Object) (*opcode.Function, err
 	return fn, nil
 }
 
+// runFunction runs function f until it returns. Function called by ICall is
+// run recursively, while function called by ITailCall replaces f in the loop.
+// Exceptions are caught here only if the handler is installed by function of
+// this frame, otherwise they are returned to the caller.
 func (i *NaiveVMInterpreter) runFunction(f *opcode.Function) (error, bool) {
 	var e error
 	var isHalt bool
 	var breakAndReturn bool
 
+	frame := i.csi
 	length := len(f.Opcodes)
 
-	for i.ip-i.fp < uint64(length) && e == nil && !isHalt {
+	for i.ip-i.fp < uint64(length) && !isHalt {
 		j := int(i.ip - i.fp)
 		i.execOne()
 
@@ -722,30 +1683,69 @@ func (i *NaiveVMInterpreter) runFunction(f *opcode.Function) (error, bool) {
 		// info := f.DebugInfo[j]
 		// fmt.Printf("%s\n", info.Message("%s", code))
 		top := i.Top()
+		csi := i.csi
 		e, isHalt = i.ExecOpcode(code)
-		if e != nil {
-			break
-		}
 
-		if isHalt {
-			break
+		if e == nil && !isHalt {
+			switch code.Name {
+			case opcode.ICall, opcode.ICallV, opcode.IPCall, opcode.IXPCall:
+				if i.csi <= csi {
+					// native method is called already
+					break
+				}
+
+				fn, err := i.getFunction(top)
+				if err != nil {
+					e = err
+					break
+				}
+
+				e, isHalt = i.runFunction(fn)
+
+			case opcode.ITailCall, opcode.ITailCallV:
+				if _, ok := top.(*object.FunctionObject); !ok {
+					break
+				}
+
+				fn, err := i.getFunction(top)
+				if err != nil {
+					e = err
+					break
+				}
+
+				f = fn
+				length = len(f.Opcodes)
+
+			case opcode.IImport:
+				if i.csi > csi {
+					// main function of module is called
+					fn, _ := i.GetFunctionInfo(int(i.fi))
+					e, isHalt = i.runFunction(fn)
+				}
+
+			case opcode.IReturn:
+				breakAndReturn = true
+			}
 		}
 
-		switch code.Name {
-		case opcode.ICall:
-			fn, err := i.getFunction(top)
-			if err != nil {
-				e = err
-				break
+		if e != nil {
+			if i.startMessageHandler(e) {
+				// Message handler of xpcall runs on top of this frame.
+				fn, _ := i.GetFunctionInfo(int(i.fi))
+				e, isHalt = i.runFunction(fn)
 			}
 
-			e, isHalt = i.runFunction(fn)
+			if i.tsi == 0 || i.tryStack[i.tsi-1].csi != frame || !i.catch(e) {
+				break
+			}
 
-		case opcode.IReturn:
-			breakAndReturn = true
+			e = nil
+			f, _ = i.GetFunctionInfo(int(i.fi))
+			length = len(f.Opcodes)
+			continue
 		}
 
-		if breakAndReturn {
+		if isHalt || breakAndReturn {
 			break
 		}
 	}
@@ -753,6 +1753,23 @@ func (i *NaiveVMInterpreter) runFunction(f *opcode.Function) (error, bool) {
 	return e, isHalt
 }
 
+// CallFunction calls fn with args from native code, and runs until fn returns.
+// Exceptions not caught in the call are returned with registers restored.
+func (i *NaiveVMInterpreter) CallFunction(fn object.Object, args ...object.Object) ([]object.Object, error) {
+	s := i.saveCallState()
+	result, done, e := i.startNestedCall(fn, args)
+	if done {
+		return result, e
+	}
+
+	f, e := i.getFunction(fn)
+	if e == nil {
+		e, _ = i.runFunction(f)
+	}
+
+	return i.finishNestedCall(s, e)
+}
+
 func (i *NaiveVMInterpreter) runEntry(index int) ([]object.Object, error) {
 	if index < 0 || index >= len(i.CodePage.Functions) {
 		return nil, NewRuntimeError("function %d not found", index)